@@ -0,0 +1,367 @@
+package structs
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var errPathNotFound = errors.New("structs: path not found")
+
+// Accessor reads and writes the values of a struct, map or slice uniformly,
+// by dotted paths of struct tag names, map keys and slice indexes, ie:
+// "address.city", "items.0.id".
+type Accessor interface {
+	// Get returns the value at path.
+	Get(path string) (interface{}, error)
+	// Set sets the value at path to v, which is converted to the type of the
+	// value if it is a number, or of the same kind.
+	Set(path string, v interface{}) error
+	// Keys returns the top level keys.
+	Keys() []string
+	// Has reports whether the value at path exists.
+	Has(path string) bool
+}
+
+var _ Accessor = (*Struct)(nil)
+
+// NewAccessor returns the Accessor of v, which is a struct, map with string
+// or integer keys, slice or array, or a pointer to them. Values must be
+// pointers, except for maps and slices, to be settable. For more info refer to
+// NewAccessorWithTag() function.
+func NewAccessor(v interface{}) (Accessor, error) {
+	return NewAccessorWithTag(v, DefaultTagName)
+}
+
+// NewAccessorWithTag is the same as NewAccessor() but with tagName, which
+// names the fields of the structs in paths.
+func NewAccessorWithTag(v interface{}, tagName string) (Accessor, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() && rv.Elem().Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+	switch reflect.Indirect(rv).Kind() { // nolint: exhaustive
+	case reflect.Struct:
+		return New(v).SetTagName(tagName), nil
+	case reflect.Map, reflect.Slice, reflect.Array:
+		return &valueAccessor{value: reflect.Indirect(rv), tagName: tagName}, nil
+	default:
+		return nil, fmt.Errorf("structs: unsupported accessor type %T", v)
+	}
+}
+
+// Get returns the value at the dotted path of tag names, see Accessor.
+func (s *Struct) Get(path string) (interface{}, error) {
+	return getPath(s.value, s.tagName, path)
+}
+
+// Set sets the value at the dotted path of tag names to v, see Accessor. The
+// struct must be given as a pointer.
+func (s *Struct) Set(path string, v interface{}) error {
+	return setPath(s.value, s.tagName, path, v)
+}
+
+// Keys returns the tag names of the exported fields, which are the keys of
+// Map() without omitting empty values. The fields of flattened structs, or
+// pointers to struct, are included by their own names, with the prefix of the
+// option "prefix".
+func (s *Struct) Keys() []string {
+	return structKeys(s.value.Type(), s.tagName)
+}
+
+// Has reports whether the value at the dotted path of tag names exists.
+func (s *Struct) Has(path string) bool {
+	_, err := getPath(s.value, s.tagName, path)
+	return err == nil
+}
+
+// valueAccessor is the Accessor of maps, slices and arrays.
+type valueAccessor struct {
+	value   reflect.Value
+	tagName string
+}
+
+func (a *valueAccessor) Get(path string) (interface{}, error) {
+	return getPath(a.value, a.tagName, path)
+}
+
+func (a *valueAccessor) Set(path string, v interface{}) error {
+	return setPath(a.value, a.tagName, path, v)
+}
+
+func (a *valueAccessor) Keys() []string {
+	if a.value.Kind() == reflect.Map {
+		keys := sortedMapKeys(a.value)
+		strs := make([]string, 0, len(keys))
+		for _, k := range keys {
+			strs = append(strs, fmt.Sprint(k.Interface()))
+		}
+		return strs
+	}
+	keys := make([]string, 0, a.value.Len())
+	for i := 0; i < a.value.Len(); i++ {
+		keys = append(keys, strconv.Itoa(i))
+	}
+	return keys
+}
+
+func (a *valueAccessor) Has(path string) bool {
+	_, err := getPath(a.value, a.tagName, path)
+	return err == nil
+}
+
+func structKeys(t reflect.Type, tagName string) []string {
+	var keys []string
+	for _, f := range getFieldTypes(t, tagName) {
+		if !f.IsExported() {
+			continue
+		}
+		_, tagOpts := parseTag(f.Tag(tagName))
+		ft := f.Type()
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Struct && tagOpts.Contains("flatten") && !tagOpts.Contains("omitnested") {
+			prefix := flattenPrefix(tagOpts)
+			for _, key := range structKeys(ft, tagName) {
+				keys = append(keys, prefix+key)
+			}
+			continue
+		}
+		keys = append(keys, f.TagName())
+	}
+	return keys
+}
+
+func splitPath(path string) ([]string, error) {
+	if path == "" {
+		return nil, errors.New("structs: empty path")
+	}
+	segments := strings.Split(path, ".")
+	for _, segment := range segments {
+		if segment == "" {
+			return nil, fmt.Errorf("structs: invalid path %q", path)
+		}
+	}
+	return segments, nil
+}
+
+// pathChild returns the value of v named segment, through pointers and
+// interfaces, or errPathNotFound.
+func pathChild(v reflect.Value, tagName, segment string) (reflect.Value, error) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}, errPathNotFound
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() { // nolint: exhaustive
+	case reflect.Struct:
+		_, index, ok := lookupTagField(v.Type(), tagName, segment)
+		if !ok {
+			return reflect.Value{}, errPathNotFound
+		}
+		f, err := v.FieldByIndexErr(index)
+		if err != nil {
+			// a nil pointer to flattened struct
+			return reflect.Value{}, errPathNotFound
+		}
+		return f, nil
+	case reflect.Map:
+		key, err := pathMapKey(v.Type().Key(), segment)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if e := v.MapIndex(key); e.IsValid() {
+			return e, nil
+		}
+		return reflect.Value{}, errPathNotFound
+	case reflect.Slice, reflect.Array:
+		i, err := strconv.Atoi(segment)
+		if err != nil || i < 0 || i >= v.Len() {
+			return reflect.Value{}, errPathNotFound
+		}
+		return v.Index(i), nil
+	default:
+		return reflect.Value{}, errPathNotFound
+	}
+}
+
+// pathMapKey converts segment to a map key of type t, which is a string or
+// integer kind.
+func pathMapKey(t reflect.Type, segment string) (reflect.Value, error) {
+	key := reflect.New(t).Elem()
+	switch t.Kind() { // nolint: exhaustive
+	case reflect.String:
+		key.SetString(segment)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(segment, 10, t.Bits())
+		if err != nil {
+			return key, errPathNotFound
+		}
+		key.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(segment, 10, t.Bits())
+		if err != nil {
+			return key, errPathNotFound
+		}
+		key.SetUint(u)
+	default:
+		return key, fmt.Errorf("structs: unsupported map key type %s", t)
+	}
+	return key, nil
+}
+
+func getPath(v reflect.Value, tagName, path string) (interface{}, error) {
+	segments, err := splitPath(path)
+	if err != nil {
+		return nil, err
+	}
+	for _, segment := range segments {
+		if v, err = pathChild(v, tagName, segment); err != nil {
+			return nil, fmt.Errorf("%w: %q", err, path)
+		}
+	}
+	return v.Interface(), nil
+}
+
+func setPath(v reflect.Value, tagName, path string, val interface{}) error {
+	segments, err := splitPath(path)
+	if err != nil {
+		return err
+	}
+	if err = setValue(v, tagName, segments, val); err != nil {
+		return fmt.Errorf("%w: %q", err, path)
+	}
+	return nil
+}
+
+func setValue(v reflect.Value, tagName string, segments []string, val interface{}) error {
+	if len(segments) == 0 {
+		if !v.CanSet() {
+			return errNotSettable
+		}
+		return assignValue(v, val)
+	}
+
+	switch v.Kind() { // nolint: exhaustive
+	case reflect.Ptr:
+		if v.IsNil() {
+			if !v.CanSet() {
+				return errPathNotFound
+			}
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return setValue(v.Elem(), tagName, segments, val)
+	case reflect.Interface:
+		if v.IsNil() {
+			return errPathNotFound
+		}
+		return setCopy(v, v.Elem(), tagName, segments, val)
+	case reflect.Struct:
+		_, index, ok := lookupTagField(v.Type(), tagName, segments[0])
+		if !ok {
+			return errPathNotFound
+		}
+		f, err := fieldByIndex(v, index)
+		if err != nil {
+			return err
+		}
+		return setValue(f, tagName, segments[1:], val)
+	case reflect.Map:
+		key, err := pathMapKey(v.Type().Key(), segments[0])
+		if err != nil {
+			return err
+		}
+		if len(segments) == 1 {
+			e := reflect.New(v.Type().Elem()).Elem()
+			if err = assignValue(e, val); err != nil {
+				return err
+			}
+			if v.IsNil() {
+				if !v.CanSet() {
+					return errNotSettable
+				}
+				v.Set(reflect.MakeMap(v.Type()))
+			}
+			v.SetMapIndex(key, e)
+			return nil
+		}
+		e := v.MapIndex(key)
+		if !e.IsValid() {
+			return errPathNotFound
+		}
+		c := reflect.New(e.Type()).Elem()
+		c.Set(e)
+		if err = setValue(c, tagName, segments[1:], val); err != nil {
+			return err
+		}
+		v.SetMapIndex(key, c)
+		return nil
+	default:
+		c, err := pathChild(v, tagName, segments[0])
+		if err != nil {
+			return err
+		}
+		return setValue(c, tagName, segments[1:], val)
+	}
+}
+
+// setCopy sets the value at segments of the copy of elem, the element of the
+// interface v, and stores the copy back in v if v is settable.
+func setCopy(v, elem reflect.Value, tagName string, segments []string, val interface{}) error {
+	c := reflect.New(elem.Type()).Elem()
+	c.Set(elem)
+	if err := setValue(c, tagName, segments, val); err != nil {
+		return err
+	}
+	if !v.CanSet() {
+		if c.Kind() != reflect.Ptr && c.Kind() != reflect.Map && c.Kind() != reflect.Slice {
+			return errNotSettable
+		}
+		return nil
+	}
+	v.Set(c)
+	return nil
+}
+
+// assignValue sets v to val, which is converted to the type of v if it is a
+// number, or of the same kind, or parsed by ParseTime() or ParseDuration() if
+// v is a time.Time or time.Duration. Numbers which overflow the type of v, or
+// lose their fractional part, are errors. A nil val sets v to zero value.
+func assignValue(v reflect.Value, val interface{}) error {
+	given, err := convertValue(v.Type(), val)
+	if err != nil {
+		return err
+	}
+	v.Set(given)
+	return nil
+}
+
+// convertValue returns val converted to type t, see assignValue.
+func convertValue(t reflect.Type, val interface{}) (reflect.Value, error) {
+	if val == nil {
+		return reflect.Zero(t), nil
+	}
+	given, ok, err := parseTimeValue(t, reflect.ValueOf(val), TimeDefault, DurationDefault)
+	if err != nil {
+		return given, err
+	}
+	if !ok && !given.Type().AssignableTo(t) {
+		if !isConvertible(given.Type(), t) {
+			return given, fmt.Errorf("structs: cannot assign %s to %s", given.Type(), t)
+		}
+		if err = checkNumber(given, t); err != nil {
+			return given, err
+		}
+		given = given.Convert(t)
+	}
+	if given.Type() != t {
+		c := reflect.New(t).Elem()
+		c.Set(given)
+		given = c
+	}
+	return given, nil
+}
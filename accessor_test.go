@@ -0,0 +1,202 @@
+package structs
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type accessorAddress struct {
+	City string `map:"city"`
+}
+
+type accessorMeta struct {
+	Note string `map:"note"`
+}
+
+type accessorUser struct {
+	Name    string                     `map:"name"`
+	Age     int                        `map:"age"`
+	Address accessorAddress            `map:"address"`
+	Home    *accessorAddress           `map:"home"`
+	Tags    []string                   `map:"tags"`
+	ByName  map[string]accessorAddress `map:"by_name"`
+	Any     interface{}                `map:"any"`
+	Meta    accessorMeta               `map:",flatten"`
+	Ignored string                     `map:"-"`
+	secret  string
+}
+
+func TestStruct_Accessor(t *testing.T) {
+	u := &accessorUser{
+		Name:    "gopher",
+		Address: accessorAddress{City: "shanghai"},
+		Tags:    []string{"a", "b"},
+		ByName:  map[string]accessorAddress{"x": {City: "x"}},
+		Any:     map[string]interface{}{"k": "v"},
+	}
+	s := New(u)
+
+	require.Equal(t, []string{"name", "age", "address", "home", "tags", "by_name", "any", "note"}, s.Keys())
+
+	v, err := s.Get("address.city")
+	require.NoError(t, err)
+	require.Equal(t, "shanghai", v)
+	v, err = s.Get("tags.1")
+	require.NoError(t, err)
+	require.Equal(t, "b", v)
+	v, err = s.Get("any.k")
+	require.NoError(t, err)
+	require.Equal(t, "v", v)
+
+	require.True(t, s.Has("by_name.x.city"))
+	require.True(t, s.Has("note"))
+	require.False(t, s.Has("home.city"))
+	require.False(t, s.Has("tags.2"))
+	require.False(t, s.Has("Ignored"))
+	require.False(t, s.Has("secret"))
+	_, err = s.Get("missing")
+	require.ErrorIs(t, err, errPathNotFound)
+
+	require.NoError(t, s.Set("name", "new"))
+	require.NoError(t, s.Set("age", int64(18)))
+	require.NoError(t, s.Set("home.city", "beijing"))
+	require.NoError(t, s.Set("tags.0", "c"))
+	require.NoError(t, s.Set("by_name.x.city", "y"))
+	require.NoError(t, s.Set("by_name.z", accessorAddress{City: "z"}))
+	require.NoError(t, s.Set("any.k", "w"))
+	require.NoError(t, s.Set("note", "n"))
+	require.Equal(t, &accessorUser{
+		Name:    "new",
+		Age:     18,
+		Address: accessorAddress{City: "shanghai"},
+		Home:    &accessorAddress{City: "beijing"},
+		Tags:    []string{"c", "b"},
+		ByName:  map[string]accessorAddress{"x": {City: "y"}, "z": {City: "z"}},
+		Any:     map[string]interface{}{"k": "w"},
+		Meta:    accessorMeta{Note: "n"},
+	}, u)
+
+	require.Error(t, s.Set("age", "18"))
+	require.Error(t, s.Set("tags.5", "x"))
+	require.Error(t, s.Set("", "x"))
+	require.Error(t, s.Set("a..b", "x"))
+	require.ErrorIs(t, New(accessorUser{}).Set("name", "x"), errNotSettable)
+}
+
+func TestNewAccessor(t *testing.T) {
+	m := map[string]interface{}{
+		"user": &accessorUser{Name: "gopher"},
+		"list": []interface{}{1, map[string]interface{}{"k": "v"}},
+		"b":    1,
+	}
+	a, err := NewAccessor(m)
+	require.NoError(t, err)
+	require.Equal(t, []string{"b", "list", "user"}, a.Keys())
+
+	v, err := a.Get("user.name")
+	require.NoError(t, err)
+	require.Equal(t, "gopher", v)
+	require.True(t, a.Has("list.1.k"))
+	require.NoError(t, a.Set("list.1.k", "w"))
+	require.NoError(t, a.Set("user.address.city", "c"))
+	require.NoError(t, a.Set("c", 2))
+	require.Equal(t, "w", m["list"].([]interface{})[1].(map[string]interface{})["k"])
+	require.Equal(t, "c", m["user"].(*accessorUser).Address.City)
+	require.Equal(t, 2, m["c"])
+
+	ids := map[int]string{1: "a"}
+	a, err = NewAccessor(ids)
+	require.NoError(t, err)
+	require.NoError(t, a.Set("2", "b"))
+	require.Equal(t, map[int]string{1: "a", 2: "b"}, ids)
+	require.False(t, a.Has("x"))
+
+	list := []accessorAddress{{City: "a"}}
+	a, err = NewAccessor(list)
+	require.NoError(t, err)
+	require.Equal(t, []string{"0"}, a.Keys())
+	require.NoError(t, a.Set("0.city", "b"))
+	require.Equal(t, "b", list[0].City)
+
+	arr := [1]int{1}
+	a, err = NewAccessor(&arr)
+	require.NoError(t, err)
+	require.NoError(t, a.Set("0", 2))
+	require.Equal(t, [1]int{2}, arr)
+
+	u := &accessorUser{}
+	a, err = NewAccessor(&u)
+	require.NoError(t, err)
+	require.NoError(t, a.Set("name", "x"))
+	require.Equal(t, "x", u.Name)
+
+	_, err = NewAccessor(1)
+	require.Error(t, err)
+}
+
+func TestStruct_AccessorNilMap(t *testing.T) {
+	u := &accessorUser{}
+	s := New(u)
+	require.NoError(t, s.Set("by_name.x", accessorAddress{City: "x"}))
+	require.Equal(t, map[string]accessorAddress{"x": {City: "x"}}, u.ByName)
+
+	u = &accessorUser{}
+	require.ErrorIs(t, New(u).Set("by_name.x.city", "x"), errPathNotFound)
+	require.Nil(t, u.ByName)
+
+	var m map[string]int
+	a, err := NewAccessor(m)
+	require.NoError(t, err)
+	require.ErrorIs(t, a.Set("x", 1), errNotSettable)
+}
+
+func TestStruct_AccessorFlattenPointer(t *testing.T) {
+	type user struct {
+		Name string        `map:"name"`
+		Meta *accessorMeta `map:",flatten"`
+	}
+
+	u := &user{}
+	s := New(u)
+	require.Equal(t, []string{"name", "note"}, s.Keys())
+	require.False(t, s.Has("note"))
+	_, err := s.Get("note")
+	require.ErrorIs(t, err, errPathNotFound)
+
+	require.NoError(t, s.Set("note", "n"))
+	require.Equal(t, &user{Meta: &accessorMeta{Note: "n"}}, u)
+	v, err := s.Get("note")
+	require.NoError(t, err)
+	require.Equal(t, "n", v)
+
+	require.ErrorIs(t, New(user{}).Set("note", "n"), errNotSettable)
+}
+
+func TestStruct_AccessorNumbers(t *testing.T) {
+	type numbers struct {
+		Small int8    `map:"small"`
+		Count uint16  `map:"count"`
+		Float float32 `map:"float"`
+		Int   int     `map:"int"`
+	}
+	v := &numbers{}
+	s := New(v)
+
+	require.NoError(t, s.Set("small", 127))
+	require.NoError(t, s.Set("small", -128.0))
+	require.NoError(t, s.Set("count", uint64(65535)))
+	require.NoError(t, s.Set("float", 1.5))
+	require.NoError(t, s.Set("int", uint8(3)))
+	require.Equal(t, &numbers{Small: -128, Count: 65535, Float: 1.5, Int: 3}, v)
+
+	require.EqualError(t, s.Set("small", 300), `structs: 300 overflows int8: "small"`)
+	require.EqualError(t, s.Set("small", uint(200)), `structs: 200 overflows int8: "small"`)
+	require.EqualError(t, s.Set("int", 3.9), `structs: 3.9 loses its fraction in int: "int"`)
+	require.EqualError(t, s.Set("int", math.Inf(1)), `structs: +Inf overflows int: "int"`)
+	require.EqualError(t, s.Set("count", -1), `structs: -1 overflows uint16: "count"`)
+	require.EqualError(t, s.Set("count", 65536.0), `structs: 65536 overflows uint16: "count"`)
+	require.EqualError(t, s.Set("float", math.MaxFloat64), `structs: 1.7976931348623157e+308 overflows float32: "float"`)
+	require.Equal(t, &numbers{Small: -128, Count: 65535, Float: 1.5, Int: 3}, v)
+}
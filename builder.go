@@ -0,0 +1,270 @@
+package structs
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// BuildErrors are the errors of Builder.Build(), one for every assignment
+// which failed and for every required field which is missing, and of
+// DynamicStruct.Type(), one for every invalid field.
+type BuildErrors []error
+
+func (e BuildErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Is reports whether any of the errors matches target, so errors.Is matches
+// any of them.
+func (e BuildErrors) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first of the errors which matches target, so errors.As
+// matches any of them.
+func (e BuildErrors) As(target interface{}) bool {
+	for _, err := range e {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Builder constructs a struct of type T by the assignments of its fields,
+// which are type checked and applied all at once by Build(). Example:
+//
+//   user, err := structs.NewBuilder(&User{}).
+//       Set("name", "fatih").
+//       Set("address.city", "Istanbul").
+//       Set("Age", 31).
+//       Build()
+type Builder[T any] struct {
+	value       *T
+	tagName     string
+	assignments []assignment
+	errs        []error
+}
+
+type assignment struct {
+	path  string
+	value interface{}
+}
+
+// NewBuilder returns a Builder of the struct v points to, whose fields are
+// set by Build(). A nil v builds a new zero struct.
+func NewBuilder[T any](v *T) *Builder[T] {
+	if v == nil {
+		v = new(T)
+	}
+	return &Builder[T]{
+		value:   v,
+		tagName: DefaultTagName,
+	}
+}
+
+// SetTagName sets the tag name of the fields in the paths, default is "map".
+func (b *Builder[T]) SetTagName(tag string) *Builder[T] {
+	b.tagName = tag
+	return b
+}
+
+// Set assigns v to the field at the dotted path, ie: "address.city", whose
+// segments are the names of the fields in the tag, or their Go names, or map
+// keys and slice indexes. Nil pointers to structs on the way are allocated. v
+// is converted the same as Accessor.Set does, so numbers are converted to the
+// type of the field, unless they overflow it or lose their fractional part,
+// and strings are parsed for time.Time and time.Duration.
+// A map[string]interface{} v of a struct field assigns the fields of it by
+// its keys, the same as SetMap().
+func (b *Builder[T]) Set(path string, v interface{}) *Builder[T] {
+	b.assignments = append(b.assignments, assignment{path: path, value: v})
+	return b
+}
+
+// SetMap assigns the values of m to the fields at their keys, in the order of
+// the keys. For more info refer to Builder types Set() method.
+func (b *Builder[T]) SetMap(m map[string]interface{}) *Builder[T] {
+	for _, k := range SortedKeys(m) {
+		b.Set(k, m[k])
+	}
+	return b
+}
+
+// SetPairs assigns the values of the key/value pairs kv, ie: "name", "fatih",
+// "age", 31, to the fields at their keys, which must be strings. For more info
+// refer to Builder types Set() method.
+func (b *Builder[T]) SetPairs(kv ...interface{}) *Builder[T] {
+	if len(kv)%2 != 0 {
+		b.errs = append(b.errs, errors.New("structs: odd number of key/value pairs"))
+		kv = kv[:len(kv)-1]
+	}
+	for i := 0; i < len(kv); i += 2 {
+		path, ok := kv[i].(string)
+		if !ok {
+			b.errs = append(b.errs, fmt.Errorf("structs: key %v of type %T is not a string", kv[i], kv[i]))
+			continue
+		}
+		b.Set(path, kv[i+1])
+	}
+	return b
+}
+
+// Build applies the assignments, in the order they were made, to the struct,
+// then checks that the fields with the rule "required" in the tag "validate",
+// of the struct and of its nested structs, are not zero, see IsZeroValue().
+// It returns the struct and BuildErrors with all the errors, if any, in which
+// case the struct is partially built.
+func (b *Builder[T]) Build() (T, error) {
+	v := reflect.ValueOf(b.value).Elem()
+	if v.Kind() != reflect.Struct {
+		return *b.value, fmt.Errorf("structs: builder of non struct type %s", v.Type())
+	}
+
+	errs := append(BuildErrors(nil), b.errs...)
+	for _, a := range b.assignments {
+		errs = b.assign(errs, v, a.path, a.value)
+	}
+	errs = b.appendRequired(errs, v, "")
+	if len(errs) > 0 {
+		return *b.value, errs
+	}
+	return *b.value, nil
+}
+
+// assign assigns val to the field of the struct v at path, and appends the
+// errors to errs.
+func (b *Builder[T]) assign(errs BuildErrors, v reflect.Value, path string, val interface{}) BuildErrors {
+	segments, err := splitPath(path)
+	if err != nil {
+		return append(errs, err)
+	}
+	field, rest, err := b.lookup(v, segments)
+	if err != nil {
+		return append(errs, fmt.Errorf("%w: %q", err, path))
+	}
+	if len(rest) > 0 {
+		if err = setValue(field.value, b.tagName, rest, val); err != nil {
+			return append(errs, fmt.Errorf("%w: %q", err, path))
+		}
+		return errs
+	}
+
+	if m, ok := val.(map[string]interface{}); ok && isMergeStruct(field.value.Type()) {
+		for _, k := range SortedKeys(m) {
+			errs = b.assign(errs, v, path+"."+k, m[k])
+		}
+		return errs
+	}
+	given, err := convertValue(field.value.Type(), val)
+	if err == nil {
+		err = field.Set(given.Interface())
+	}
+	if err != nil {
+		return append(errs, fmt.Errorf("%w: %q", err, path))
+	}
+	return errs
+}
+
+// lookup returns the field of the struct v at segments, through nested
+// structs, allocating nil pointers, and the remaining segments of a field
+// which is not a struct.
+func (b *Builder[T]) lookup(v reflect.Value, segments []string) (*Field, []string, error) {
+	for i, segment := range segments {
+		field, ok := b.field(v.Type(), segment)
+		if !ok {
+			return nil, nil, errPathNotFound
+		}
+		fv, err := fieldByIndex(v, field.Index)
+		if err != nil {
+			return nil, nil, err
+		}
+		f := &Field{value: fv, field: field, defaultTag: b.tagName}
+		if i == len(segments)-1 {
+			return f, nil, nil
+		}
+		for fv.Kind() == reflect.Ptr && fv.Type().Elem().Kind() == reflect.Struct {
+			if fv.IsNil() {
+				fv.Set(reflect.New(fv.Type().Elem()))
+			}
+			fv = fv.Elem()
+		}
+		if fv.Kind() != reflect.Struct {
+			return f, segments[i+1:], nil
+		}
+		v = fv
+	}
+	return nil, nil, errPathNotFound
+}
+
+// field returns the exported field of struct type t named name in the tag,
+// or by its Go name.
+func (b *Builder[T]) field(t reflect.Type, name string) (reflect.StructField, bool) {
+	if field, index, ok := lookupTagField(t, b.tagName, name); ok {
+		field.Index = index
+		return field, true
+	}
+	field, ok := t.FieldByName(name)
+	if !ok || !field.IsExported() || field.Tag.Get(b.tagName) == "-" {
+		return reflect.StructField{}, false
+	}
+	return field, true
+}
+
+// appendRequired appends the errors of the required fields of the struct v,
+// whose paths are prefixed by prefix, which are zero to errs.
+func (b *Builder[T]) appendRequired(errs BuildErrors, v reflect.Value, prefix string) BuildErrors {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		tag := field.Tag.Get(b.tagName)
+		if tag == "-" {
+			continue
+		}
+		name, tagOpts := parseTag(tag)
+		if name == "" {
+			name = field.Name
+		}
+		fv := v.Field(i)
+		if isRequired(field.Tag.Get("validate")) && isOmitZero(fv) {
+			errs = append(errs, fmt.Errorf("structs: required field %q is missing", prefix+name))
+			continue
+		}
+
+		ev := reflect.Indirect(fv)
+		if tagOpts.Contains("omitnested") || !ev.IsValid() || ev.Type() == timeType || !isMergeStruct(ev.Type()) {
+			continue
+		}
+		if tagOpts.Contains("flatten") {
+			errs = b.appendRequired(errs, ev, prefix+flattenPrefix(tagOpts))
+		} else {
+			errs = b.appendRequired(errs, ev, prefix+name+".")
+		}
+	}
+	return errs
+}
+
+// isRequired reports whether the rules of the tag "validate" contain
+// "required".
+func isRequired(rules string) bool {
+	for _, rule := range strings.Split(rules, ",") {
+		if rule == "required" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,159 @@
+package structs
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type builderAddress struct {
+	City string `map:"city" validate:"required"`
+	Zip  string `map:"zip"`
+}
+
+type builderLog struct {
+	Level string `map:"level"`
+}
+
+type builderUser struct {
+	Name     string            `map:"name" validate:"required"`
+	Age      int               `map:"age"`
+	Timeout  time.Duration     `map:"timeout"`
+	Since    time.Time         `map:"since"`
+	Address  *builderAddress   `map:"address"`
+	Tags     []string          `map:"tags"`
+	Labels   map[string]string `map:"labels"`
+	Extra    interface{}       `map:"extra"`
+	Log      builderLog        `map:",flatten,prefix=log_"`
+	Ignored  string            `map:"-"`
+	internal string
+}
+
+func TestBuilder(t *testing.T) {
+	user, err := NewBuilder(&builderUser{}).
+		Set("name", "fatih").
+		Set("Age", int64(31)).
+		Set("timeout", "1m").
+		Set("since", "2021-01-02T03:04:05Z").
+		Set("address.city", "Istanbul").
+		Set("Address.Zip", "34000").
+		Set("tags", []string{"a", "b"}).
+		Set("labels", map[string]string{"k": "v"}).
+		Set("labels.x", "y").
+		Set("extra", 1.5).
+		Set("log_level", "debug").
+		Build()
+	require.NoError(t, err)
+	require.Equal(t, builderUser{
+		Name:    "fatih",
+		Age:     31,
+		Timeout: time.Minute,
+		Since:   time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC),
+		Address: &builderAddress{City: "Istanbul", Zip: "34000"},
+		Tags:    []string{"a", "b"},
+		Labels:  map[string]string{"k": "v", "x": "y"},
+		Extra:   1.5,
+		Log:     builderLog{Level: "debug"},
+	}, user)
+}
+
+func TestBuilder_SetMap(t *testing.T) {
+	user, err := NewBuilder[builderUser](nil).
+		SetMap(map[string]interface{}{
+			"name": "fatih",
+			"age":  uint8(31),
+			"address": map[string]interface{}{
+				"city": "Istanbul",
+			},
+			"extra": nil,
+		}).
+		Build()
+	require.NoError(t, err)
+	require.Equal(t, builderUser{
+		Name:    "fatih",
+		Age:     31,
+		Address: &builderAddress{City: "Istanbul"},
+	}, user)
+}
+
+func TestBuilder_SetPairs(t *testing.T) {
+	user, err := NewBuilder(&builderUser{Age: 20}).
+		SetPairs("name", "fatih", "address.city", "Istanbul").
+		Build()
+	require.NoError(t, err)
+	require.Equal(t, "fatih", user.Name)
+	require.Equal(t, 20, user.Age)
+	require.Equal(t, "Istanbul", user.Address.City)
+
+	_, err = NewBuilder(&builderUser{}).
+		SetPairs("name", "fatih", 1, 2, "age").
+		Build()
+	require.EqualError(t, err, "structs: odd number of key/value pairs; structs: key 1 of type int is not a string")
+}
+
+func TestBuilder_Errors(t *testing.T) {
+	user, err := NewBuilder(&builderUser{}).
+		Set("age", "old").
+		Set("age", uint64(math.MaxUint64)).
+		Set("age", 1.5).
+		Set("unknown", 1).
+		Set("Ignored", "x").
+		Set("internal", "x").
+		Set("timeout", "soon").
+		Set("address.zip", "34000").
+		Set("tags.5", "x").
+		Set("", 1).
+		Build()
+	require.Error(t, err)
+
+	var errs BuildErrors
+	require.True(t, errors.As(err, &errs))
+	require.Len(t, errs, 11)
+	require.EqualError(t, errs[0], `structs: cannot assign string to int: "age"`)
+	require.EqualError(t, errs[1], `structs: 18446744073709551615 overflows int: "age"`)
+	require.EqualError(t, errs[2], `structs: 1.5 loses its fraction in int: "age"`)
+	require.ErrorIs(t, errs[3], errPathNotFound)
+	require.ErrorIs(t, errs[4], errPathNotFound)
+	require.ErrorIs(t, errs[5], errPathNotFound)
+	require.Error(t, errs[6])
+	require.ErrorIs(t, errs[7], errPathNotFound)
+	require.EqualError(t, errs[8], "structs: empty path")
+	require.EqualError(t, errs[9], `structs: required field "name" is missing`)
+	require.EqualError(t, errs[10], `structs: required field "address.city" is missing`)
+	require.ErrorIs(t, err, errPathNotFound)
+
+	// the assignments which succeeded are applied
+	require.Equal(t, 0, user.Age)
+	require.Equal(t, "34000", user.Address.Zip)
+}
+
+func TestBuilder_TagName(t *testing.T) {
+	type T struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}
+	v, err := NewBuilder(&T{}).SetTagName("json").Set("name", "fatih").Set("Age", 31).Build()
+	require.NoError(t, err)
+	require.Equal(t, T{Name: "fatih", Age: 31}, v)
+}
+
+func TestBuilder_FlattenPointer(t *testing.T) {
+	type T struct {
+		Name    string          `map:"name"`
+		Address *builderAddress `map:",flatten,prefix=addr_"`
+	}
+	v, err := NewBuilder(&T{}).Set("addr_city", "shanghai").Build()
+	require.NoError(t, err)
+	require.Equal(t, T{Address: &builderAddress{City: "shanghai"}}, v)
+
+	_, err = NewBuilder(&T{}).Set("addr_zip", "200000").Build()
+	require.EqualError(t, err, `structs: required field "addr_city" is missing`)
+}
+
+func TestBuilder_NotStruct(t *testing.T) {
+	_, err := NewBuilder(new(int)).Set("a", 1).Build()
+	require.Error(t, err)
+}
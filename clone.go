@@ -0,0 +1,176 @@
+package structs
+
+import (
+	"reflect"
+	"sync"
+)
+
+// CloneTagName is the tag name of the clone options of struct fields.
+const CloneTagName = "clone"
+
+// ClonerFunc returns a deep copy of v, which is a value of the type it is
+// registered for.
+type ClonerFunc func(v interface{}) interface{}
+
+var cloners sync.Map // reflect.Type -> ClonerFunc
+
+// RegisterCloner registers the custom cloner fn of type t, which is used by
+// Clone instead of copying the values of t field by field, ie: *big.Int, or
+// types containing a sync.Mutex. A nil fn removes the cloner of t.
+func RegisterCloner(t reflect.Type, fn ClonerFunc) {
+	if fn == nil {
+		cloners.Delete(t)
+		return
+	}
+	cloners.Store(t, fn)
+}
+
+func lookupCloner(t reflect.Type) (ClonerFunc, bool) {
+	fn, ok := cloners.Load(t)
+	if !ok {
+		return nil, false
+	}
+	return fn.(ClonerFunc), true
+}
+
+// Clone returns a deep copy of v. Pointers, slices, maps, arrays, interfaces
+// and the exported fields of structs are copied recursively, references shared
+// in v are shared in the copy as well, cycles included. Unexported fields,
+// channels and functions are copied as is. A struct tag of "clone" changes the
+// copy of that particular field. Example:
+//
+//   // Field is left as zero value in the copy.
+//   Field *Cache `clone:"-"`
+//
+//   // Field is copied as is, sharing its references with v.
+//   Field *Config `clone:"shallow"`
+//
+// The values of types with a cloner registered by RegisterCloner are copied
+// by the cloner.
+func Clone(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	return newCloner().copy(reflect.ValueOf(v)).Interface()
+}
+
+// CloneOf is the same as Clone() but keeps the type of v.
+func CloneOf[T any](v T) T {
+	c, _ := Clone(v).(T)
+	return c
+}
+
+type cloneKey struct {
+	typ reflect.Type
+	ptr uintptr
+	len int
+}
+
+type cloner struct {
+	seen map[cloneKey]reflect.Value
+	// field copies the struct field f of src to dst, it returns false if the
+	// field is not handled, which is copied deeply.
+	field func(f reflect.StructField, dst, src reflect.Value) bool
+}
+
+func newCloner() *cloner {
+	c := &cloner{seen: make(map[cloneKey]reflect.Value)}
+	c.field = c.cloneField
+	return c
+}
+
+// cloneField copies the struct field with the options of the "clone" tag.
+func (c *cloner) cloneField(f reflect.StructField, dst, src reflect.Value) bool {
+	switch f.Tag.Get(CloneTagName) {
+	case "-":
+		dst.Set(reflect.Zero(f.Type))
+		return true
+	case "shallow":
+		dst.Set(src)
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *cloner) copy(v reflect.Value) reflect.Value {
+	if fn, ok := lookupCloner(v.Type()); ok {
+		out := reflect.New(v.Type()).Elem()
+		if r := fn(v.Interface()); r != nil {
+			out.Set(reflect.ValueOf(r))
+		}
+		return out
+	}
+
+	switch v.Kind() { // nolint: exhaustive
+	case reflect.Struct:
+		out := reflect.New(v.Type()).Elem()
+		out.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			if !c.field(field, out.Field(i), v.Field(i)) {
+				out.Field(i).Set(c.copy(v.Field(i)))
+			}
+		}
+		return out
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		key := cloneKey{typ: v.Type(), ptr: v.Pointer()}
+		if out, ok := c.seen[key]; ok {
+			return out
+		}
+		out := reflect.New(v.Type().Elem())
+		c.seen[key] = out
+		out.Elem().Set(c.copy(v.Elem()))
+		return out
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.New(v.Type()).Elem()
+		out.Set(c.copy(v.Elem()))
+		return out
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		key := cloneKey{typ: v.Type(), ptr: v.Pointer(), len: v.Len()}
+		if out, ok := c.seen[key]; ok {
+			return out
+		}
+		out := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		c.seen[key] = out
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(c.copy(v.Index(i)))
+		}
+		return out
+	case reflect.Array:
+		out := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(c.copy(v.Index(i)))
+		}
+		return out
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		key := cloneKey{typ: v.Type(), ptr: v.Pointer()}
+		if out, ok := c.seen[key]; ok {
+			return out
+		}
+		out := reflect.MakeMapWithSize(v.Type(), v.Len())
+		c.seen[key] = out
+		iter := v.MapRange()
+		for iter.Next() {
+			out.SetMapIndex(iter.Key(), c.copy(iter.Value()))
+		}
+		return out
+	default:
+		return v
+	}
+}
@@ -0,0 +1,116 @@
+package structs
+
+import (
+	"math/big"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type cloneNode struct {
+	Name     string
+	Next     *cloneNode
+	Children []*cloneNode
+	Attrs    map[string]interface{}
+	Matrix   [2][]int
+	Cache    *cloneCache `clone:"-"`
+	Config   *cloneCache `clone:"shallow"`
+	private  *int
+}
+
+type cloneCache struct {
+	mu    sync.Mutex
+	Items map[string]int
+}
+
+func TestClone(t *testing.T) {
+	n := 1
+	shared := &cloneNode{Name: "shared"}
+	src := &cloneNode{
+		Name:     "root",
+		Children: []*cloneNode{shared, shared},
+		Attrs:    map[string]interface{}{"list": []string{"a"}, "node": shared},
+		Matrix:   [2][]int{{1, 2}, {3}},
+		Cache:    &cloneCache{Items: map[string]int{"a": 1}},
+		Config:   &cloneCache{Items: map[string]int{"b": 2}},
+		private:  &n,
+	}
+	src.Next = src
+
+	got, ok := Clone(src).(*cloneNode)
+	require.True(t, ok)
+	require.NotSame(t, src, got)
+	require.Equal(t, "root", got.Name)
+
+	// cycles and shared references are preserved
+	require.Same(t, got, got.Next)
+	require.NotSame(t, shared, got.Children[0])
+	require.Same(t, got.Children[0], got.Children[1])
+	require.Same(t, got.Children[0], got.Attrs["node"])
+
+	// deep copies
+	got.Attrs["list"].([]string)[0] = "b"
+	got.Matrix[0][0] = 100
+	require.Equal(t, []string{"a"}, src.Attrs["list"])
+	require.Equal(t, 1, src.Matrix[0][0])
+
+	// clone tags and unexported fields
+	require.Nil(t, got.Cache)
+	require.Same(t, src.Config, got.Config)
+	require.Same(t, src.private, got.private)
+
+	require.Nil(t, Clone(nil))
+	require.Equal(t, 1, Clone(1))
+	require.Equal(t, []int{1, 2}, Clone([]int{1, 2}))
+}
+
+func TestCloneOf(t *testing.T) {
+	src := cloneNode{Name: "n", Children: []*cloneNode{{Name: "c"}}}
+	got := CloneOf(src)
+	require.Equal(t, src, got)
+	require.NotSame(t, src.Children[0], got.Children[0])
+
+	m := CloneOf(map[string][]int{"a": {1}})
+	require.Equal(t, map[string][]int{"a": {1}}, m)
+
+	var e error
+	require.Nil(t, CloneOf(e))
+}
+
+func TestRegisterCloner(t *testing.T) {
+	bigType := reflect.TypeOf((*big.Int)(nil))
+	RegisterCloner(bigType, func(v interface{}) interface{} {
+		return new(big.Int).Set(v.(*big.Int))
+	})
+	defer RegisterCloner(bigType, nil)
+
+	cacheType := reflect.TypeOf((*cloneCache)(nil))
+	RegisterCloner(cacheType, func(v interface{}) interface{} {
+		c := v.(*cloneCache)
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		items := make(map[string]int, len(c.Items))
+		for k, v := range c.Items {
+			items[k] = v
+		}
+		return &cloneCache{Items: items}
+	})
+	defer RegisterCloner(cacheType, nil)
+
+	type A struct {
+		N     *big.Int
+		Cache *cloneCache
+	}
+	src := &A{N: big.NewInt(10), Cache: &cloneCache{Items: map[string]int{"a": 1}}}
+
+	got := CloneOf(src)
+	require.NotSame(t, src.N, got.N)
+	require.Equal(t, 0, src.N.Cmp(got.N))
+	require.NotSame(t, src.Cache, got.Cache)
+	require.Equal(t, map[string]int{"a": 1}, got.Cache.Items)
+
+	got.Cache.Items["a"] = 2
+	require.Equal(t, 1, src.Cache.Items["a"])
+}
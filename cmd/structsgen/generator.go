@@ -0,0 +1,887 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/types"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/things-go/structs"
+)
+
+const structsPkgPath = "github.com/things-go/structs"
+
+// Generator generates the Map, Values, Names, IsZero and FromMap methods of
+// struct types, which reproduce the behaviour of the structs package without
+// reflection.
+type Generator struct {
+	pkg     *types.Package
+	tagName string
+	types   map[*types.TypeName]bool
+	imports map[string]string // path -> name
+	buf     bytes.Buffer
+	tmp     int
+}
+
+// NewGenerator returns a new *Generator for the package pkg with tagName.
+func NewGenerator(pkg *types.Package, tagName string) *Generator {
+	return &Generator{
+		pkg:     pkg,
+		tagName: tagName,
+		types:   make(map[*types.TypeName]bool),
+		imports: make(map[string]string),
+	}
+}
+
+// Generate returns the formatted source of the methods of the named struct
+// types in the package.
+func (g *Generator) Generate(names []string) ([]byte, error) {
+	var objs []*types.TypeName
+	for _, name := range names {
+		obj, ok := g.pkg.Scope().Lookup(name).(*types.TypeName)
+		if !ok {
+			return nil, fmt.Errorf("structsgen: type %s not found", name)
+		}
+		if _, ok = obj.Type().Underlying().(*types.Struct); !ok {
+			return nil, fmt.Errorf("structsgen: type %s is not a struct", name)
+		}
+		g.types[obj] = true
+		objs = append(objs, obj)
+	}
+
+	for _, obj := range objs {
+		g.generate(obj)
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by structsgen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&out, "package %s\n\n", g.pkg.Name())
+	if len(g.imports) > 0 {
+		paths := make([]string, 0, len(g.imports))
+		for path := range g.imports {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		fmt.Fprintf(&out, "import (\n")
+		for _, path := range paths {
+			if !strings.Contains(path, ".") {
+				fmt.Fprintf(&out, "\t%q\n", path)
+			}
+		}
+		fmt.Fprintf(&out, "\n")
+		for _, path := range paths {
+			if strings.Contains(path, ".") {
+				fmt.Fprintf(&out, "\t%q\n", path)
+			}
+		}
+		fmt.Fprintf(&out, ")\n")
+	}
+	out.Write(g.buf.Bytes())
+
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("structsgen: invalid generated source: %w\n%s", err, out.Bytes())
+	}
+	return src, nil
+}
+
+// field is an exported struct field which is not ignored by the tag.
+type field struct {
+	goName string
+	name   string
+	typ    types.Type
+	opts   structs.TagOptions
+}
+
+func (f *field) has(opt string) bool {
+	return f.opts.Has(opt)
+}
+
+func (f *field) value(opt string) (string, bool) {
+	return f.opts.Value(opt)
+}
+
+// redactMode returns the name of the structs RedactMode constant of the
+// option of "redact" or "sensitive", the boolean reports whether the field is
+// sensitive.
+func (f *field) redactMode() (string, bool) {
+	modes := map[string]string{
+		"full":  "RedactFull",
+		"last4": "RedactLast4",
+		"hash":  "RedactHash",
+		"none":  "RedactNone",
+	}
+	for _, name := range []string{"redact", "sensitive"} {
+		if f.has(name) {
+			return "RedactDefault", true
+		}
+		if v, ok := f.value(name); ok {
+			mode, ok := modes[v]
+			if !ok {
+				mode = "RedactFull"
+			}
+			return mode, true
+		}
+	}
+	return "", false
+}
+
+// fields returns the exported fields of st which are not ignored. all also
+// includes the unexported fields, which are part of Names().
+func (g *Generator) fields(st *types.Struct) (fields []*field, all []string) {
+	for i := 0; i < st.NumFields(); i++ {
+		v := st.Field(i)
+		tag := reflect.StructTag(st.Tag(i)).Get(g.tagName)
+		if tag == "-" {
+			continue
+		}
+		all = append(all, v.Name())
+		if !v.Exported() {
+			continue
+		}
+		name, opts := structs.ParseTag(tag)
+		if name == "" {
+			name = v.Name()
+		}
+		fields = append(fields, &field{v.Name(), name, v.Type(), opts})
+	}
+	return fields, all
+}
+
+func (g *Generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+func (g *Generator) use(path, name string) string {
+	g.imports[path] = name
+	return name
+}
+
+func (g *Generator) temp(prefix string) string {
+	g.tmp++
+	return prefix + strconv.Itoa(g.tmp)
+}
+
+func (g *Generator) typeString(t types.Type) string {
+	return types.TypeString(t, func(p *types.Package) string {
+		if p == g.pkg {
+			return ""
+		}
+		return g.use(p.Path(), p.Name())
+	})
+}
+
+func (g *Generator) generate(obj *types.TypeName) {
+	name := obj.Name()
+	fields, all := g.fields(obj.Type().Underlying().(*types.Struct))
+
+	// ToMap
+	g.printf("\n// ToMap converts %s to a map[string]interface{}, the same as structs.Map.\n", name)
+	g.printf("func (x %s) ToMap() map[string]interface{} {\n", name)
+	g.printf("out := make(map[string]interface{}, %d)\n", len(fields))
+	for _, f := range fields {
+		g.genMapField(f)
+	}
+	g.printf("return out\n}\n")
+
+	// Values
+	g.printf("\n// Values converts the %s field values to a []interface{}, the same as structs.Values.\n", name)
+	g.printf("func (x %s) Values() []interface{} {\n", name)
+	g.printf("out := make([]interface{}, 0, %d)\n", len(fields))
+	for _, f := range fields {
+		g.genValuesField(f)
+	}
+	g.printf("return out\n}\n")
+
+	// Names
+	g.printf("\n// Names returns the %s field names, the same as structs.Names.\n", name)
+	g.printf("func (x %s) Names() []string {\n", name)
+	g.printf("return []string{")
+	for _, n := range all {
+		g.printf("%q,", n)
+	}
+	g.printf("}\n}\n")
+
+	// IsZero
+	g.printf("\n// IsZero returns true if all fields of %s are zero values, the same as structs.IsZero.\n", name)
+	g.printf("func (x %s) IsZero() bool {\n", name)
+	for _, f := range fields {
+		g.genIsZeroField(f)
+	}
+	g.printf("return true\n}\n")
+
+	// FromMap
+	g.printf("\n// FromMap sets the fields of %s from m, which is keyed the same as the output of ToMap.\n", name)
+	g.printf("// A value must have the type of the field, or be a map[string]interface{} for a\n")
+	g.printf("// field of generated struct type. Missing keys leave the field untouched.\n")
+	g.printf("func (x *%s) FromMap(m map[string]interface{}) error {\n", name)
+	for _, f := range fields {
+		g.genFromMapField(f)
+	}
+	g.printf("return nil\n}\n")
+}
+
+func (g *Generator) genMapField(f *field) {
+	expr := "x." + f.goName
+	if f.has("omitempty") {
+		if cond := g.emptyValue(expr, f.typ); cond != "" {
+			g.printf("if %s {\n", negate(cond))
+			defer g.printf("}\n")
+		}
+	}
+	if f.has("omitzero") {
+		g.printf("if !%s.IsZeroValue(%s) {\n", g.use(structsPkgPath, "structs"), expr)
+		defer g.printf("}\n")
+	}
+	if mode, ok := f.redactMode(); ok {
+		structs := g.use(structsPkgPath, "structs")
+		g.printf("out[%q] = %s.Mask(%s, %s.%s)\n", f.name, structs, expr, structs, mode)
+		return
+	}
+	if format, ok := f.value("format"); ok {
+		g.printf("out[%q] = %s\n", f.name, g.formatValue(expr, f.typ, format))
+		return
+	}
+	if f.has("string") {
+		if !g.toString(expr, f.typ, func(str string) { g.printf("out[%q] = %s\n", f.name, str) }) {
+			return
+		}
+		defer g.printf("}\n")
+	}
+	if f.has("omitnested") {
+		g.printf("out[%q] = %s\n", f.name, expr)
+		return
+	}
+
+	if !g.isNested(f.typ) {
+		if g.isSensitive(f.typ, make(map[types.Type]bool)) {
+			// slices and maps of interfaces may hold sensitive fields
+			expr = fmt.Sprintf("%s.RedactedWithTag(%s, %q)", g.use(structsPkgPath, "structs"), expr, g.tagName)
+		}
+		g.printf("out[%q] = %s\n", f.name, expr)
+		return
+	}
+	v := g.temp("v")
+	g.printf("var %s interface{}\n", v)
+	g.nested(v, expr, f.typ)
+	typ := f.typ
+	if p, ok := typ.Underlying().(*types.Pointer); ok {
+		typ = p.Elem()
+	}
+	switch typ.Underlying().(type) {
+	case *types.Struct, *types.Map:
+		if f.has("flatten") {
+			key := "k"
+			if prefix, _ := f.value("prefix"); prefix != "" {
+				key = strconv.Quote(prefix) + " + k"
+			}
+			// the struct is kept as is if its map is empty, like Map.
+			g.printf("if mm, ok := %s.(map[string]interface{}); ok {\n", v)
+			g.printf("for k, e := range mm {\nout[%s] = e\n}\n", key)
+			g.printf("} else {\nout[%q] = %s\n}\n", f.name, v)
+			return
+		}
+	}
+	g.printf("out[%q] = %s\n", f.name, v)
+}
+
+// formatValue returns the expression formatting expr of type t with the
+// option of "format", like the structs FormatValue.
+func (g *Generator) formatValue(expr string, t types.Type, format string) string {
+	switch {
+	case isTime(t):
+		switch structs.TimeFormat(format) {
+		case structs.TimeRFC3339:
+			return fmt.Sprintf("%s.Format(%s.RFC3339)", expr, g.use("time", "time"))
+		case structs.TimeUnix:
+			return expr + ".Unix()"
+		case structs.TimeUnixMilli:
+			return expr + ".UnixMilli()"
+		}
+		return fmt.Sprintf("%s.Format(%q)", expr, format)
+	case isDuration(t) && structs.DurationFormat(format) == structs.DurationString:
+		return expr + ".String()"
+	case isDuration(t) && structs.DurationFormat(format) == structs.DurationNanos:
+		return fmt.Sprintf("int64(%s)", expr)
+	case isPointerOrInterface(t):
+		return fmt.Sprintf("%s.FormatValue(%s, %q)", g.use(structsPkgPath, "structs"), expr, format)
+	default:
+		return fmt.Sprintf("%s.Sprintf(%q, %s)", g.use("fmt", "fmt"), format, expr)
+	}
+}
+
+// genParseTime writes the case of a string or number value for the field f
+// of time.Time, time.Duration or a pointer to them with the option of
+// "format", the reverse of formatValue.
+func (g *Generator) genParseTime(expr string, f *field, format string) {
+	t, ptr := f.typ, false
+	if p, ok := t.Underlying().(*types.Pointer); ok {
+		t, ptr = p.Elem(), true
+	}
+	var parse string
+	structs := g.use(structsPkgPath, "structs")
+	switch {
+	case isTime(t):
+		parse = fmt.Sprintf("%s.ParseTime(vv, %q)", structs, format)
+	case isDuration(t):
+		parse = fmt.Sprintf("%s.ParseDuration(vv, %q)", structs, format)
+	default:
+		return
+	}
+	g.printf("case string, int, int64, float64:\nt, err := %s\nif err != nil {\nreturn %s.Errorf(\"structs: field %s: %%w\", err)\n}\n",
+		parse, g.use("fmt", "fmt"), f.goName)
+	if ptr {
+		g.printf("%s = &t\n", expr)
+	} else {
+		g.printf("%s = t\n", expr)
+	}
+}
+
+func isTime(t types.Type) bool {
+	n, ok := t.(*types.Named)
+	return ok && n.Obj().Pkg() != nil && n.Obj().Pkg().Path() == "time" && n.Obj().Name() == "Time"
+}
+
+func isDuration(t types.Type) bool {
+	n, ok := t.(*types.Named)
+	return ok && n.Obj().Pkg() != nil && n.Obj().Pkg().Path() == "time" && n.Obj().Name() == "Duration"
+}
+
+func isPointerOrInterface(t types.Type) bool {
+	switch t.Underlying().(type) {
+	case *types.Pointer, *types.Interface:
+		return true
+	}
+	return false
+}
+
+// toString writes the conversion of expr to a string, like the structs
+// toString, calling emit with the string expression in every successful
+// branch. It reports whether the conversion may fail, in which case the
+// caller continues in an else branch which must be closed.
+func (g *Generator) toString(expr string, t types.Type, emit func(str string)) bool {
+	if conv := g.formatBasic(expr, t); conv != "" {
+		emit(conv)
+		return false
+	}
+	switch t.Underlying().(type) {
+	case *types.Pointer, *types.Interface:
+	default:
+		if hasStringMethod(t) {
+			emit(expr + ".String()")
+			return false
+		}
+	}
+	if p, ok := t.Underlying().(*types.Pointer); ok {
+		if conv := g.formatBasic("*"+expr, p.Elem()); conv != "" {
+			g.printf("if %s != nil {\n", expr)
+			emit(conv)
+			g.printf("} else ")
+		}
+	}
+	g.printf("if s, ok := interface{}(%s).(%s.Stringer); ok {\n", expr, g.use("fmt", "fmt"))
+	emit("s.String()")
+	g.printf("} else {\n")
+	return true
+}
+
+// hasStringMethod reports whether t implements fmt.Stringer.
+func hasStringMethod(t types.Type) bool {
+	sel := types.NewMethodSet(t).Lookup(nil, "String")
+	if sel == nil {
+		return false
+	}
+	sig := sel.Type().(*types.Signature)
+	if sig.Params().Len() != 0 || sig.Results().Len() != 1 {
+		return false
+	}
+	b, ok := sig.Results().At(0).Type().(*types.Basic)
+	return ok && b.Kind() == types.String
+}
+
+// negate returns the negation of the condition cond.
+func negate(cond string) string {
+	switch {
+	case strings.HasPrefix(cond, "!") && !strings.Contains(cond, " "):
+		return cond[1:]
+	case strings.Count(cond, " == ") == 1 && !strings.Contains(cond, "||"):
+		return strings.Replace(cond, " == ", " != ", 1)
+	}
+	return "!(" + cond + ")"
+}
+
+// formatBasic returns the expression formatting expr of basic type t, or an
+// empty string if t is not a bool, integer or float.
+func (g *Generator) formatBasic(expr string, t types.Type) string {
+	b, ok := t.Underlying().(*types.Basic)
+	if !ok {
+		return ""
+	}
+	switch b.Kind() { // nolint: exhaustive
+	case types.Bool:
+		return g.use("strconv", "strconv") + ".FormatBool(bool(" + expr + "))"
+	case types.Int, types.Int8, types.Int16, types.Int32, types.Int64:
+		return g.use("strconv", "strconv") + ".FormatInt(int64(" + expr + "), 10)"
+	case types.Uint, types.Uint8, types.Uint16, types.Uint32, types.Uint64:
+		return g.use("strconv", "strconv") + ".FormatUint(uint64(" + expr + "), 10)"
+	case types.Float32, types.Float64:
+		return g.use("strconv", "strconv") + ".FormatFloat(float64(" + expr + "), 'f', -1, 64)"
+	}
+	return ""
+}
+
+// genParseString writes the case of a string value for the field f of basic or
+// pointer to basic type with the option of "string".
+func (g *Generator) genParseString(expr string, f *field) {
+	t, ptr := f.typ, false
+	if p, ok := t.Underlying().(*types.Pointer); ok {
+		t, ptr = p.Elem(), true
+	}
+	parse := g.parseBasic("vv", t)
+	if parse == "" {
+		return
+	}
+	g.printf("case string:\nn, err := %s\nif err != nil {\nreturn %s.Errorf(\"structs: field %s: %%w\", err)\n}\n",
+		parse, g.use("fmt", "fmt"), f.goName)
+	if ptr {
+		g.printf("val := %s(n)\n%s = &val\n", g.typeString(t), expr)
+	} else {
+		g.printf("%s = %s(n)\n", expr, g.typeString(t))
+	}
+}
+
+// parseBasic returns the expression parsing the string expr to the basic
+// type t, the reverse of formatBasic, or an empty string if t is not a bool,
+// integer or float.
+func (g *Generator) parseBasic(expr string, t types.Type) string {
+	b, ok := t.Underlying().(*types.Basic)
+	if !ok {
+		return ""
+	}
+	strconv := g.use("strconv", "strconv")
+	switch b.Kind() { // nolint: exhaustive
+	case types.Bool:
+		return strconv + ".ParseBool(" + expr + ")"
+	case types.Int, types.Int64:
+		return strconv + ".ParseInt(" + expr + ", 10, 64)"
+	case types.Int8, types.Int16, types.Int32:
+		return fmt.Sprintf("%s.ParseInt(%s, 10, %d)", strconv, expr, 8<<(b.Kind()-types.Int8))
+	case types.Uint, types.Uint64:
+		return strconv + ".ParseUint(" + expr + ", 10, 64)"
+	case types.Uint8, types.Uint16, types.Uint32:
+		return fmt.Sprintf("%s.ParseUint(%s, 10, %d)", strconv, expr, 8<<(b.Kind()-types.Uint8))
+	case types.Float32:
+		return strconv + ".ParseFloat(" + expr + ", 32)"
+	case types.Float64:
+		return strconv + ".ParseFloat(" + expr + ", 64)"
+	}
+	return ""
+}
+
+// emptyValue returns the condition of expr being empty, like the structs
+// isEmptyValue, or an empty string if it is never empty.
+func (g *Generator) emptyValue(expr string, t types.Type) string {
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case u.Kind() == types.Bool:
+			return "!" + expr
+		case u.Info()&types.IsString != 0:
+			return "len(" + expr + ") == 0"
+		case u.Info()&(types.IsInteger|types.IsFloat) != 0:
+			return expr + " == 0"
+		case u.Kind() == types.UnsafePointer:
+			return ""
+		}
+	case *types.Array, *types.Slice, *types.Map, *types.Chan:
+		return "len(" + expr + ") == 0"
+	case *types.Pointer, *types.Interface:
+		return expr + " == nil"
+	}
+	return ""
+}
+
+// emptyWithAll returns the condition of expr being empty, like the structs
+// isEmptyWithAll.
+func (g *Generator) emptyWithAll(expr string, t types.Type) string {
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		if u.Info()&types.IsComplex != 0 {
+			return expr + " == 0"
+		}
+		if u.Kind() == types.UnsafePointer {
+			return expr + " == nil"
+		}
+	case *types.Pointer:
+		return expr + " == nil || " + g.emptyWithAll("*"+expr, u.Elem())
+	case *types.Interface, *types.Signature:
+		return expr + " == nil"
+	case *types.Struct:
+		zero := g.typeString(t) + "{}"
+		if types.Comparable(t) {
+			return expr + " == (" + zero + ")"
+		}
+		return g.use("reflect", "reflect") + ".DeepEqual(" + expr + ", " + zero + ")"
+	}
+	return g.emptyValue(expr, t)
+}
+
+// structKind classifies the struct type t.
+type structKind int
+
+const (
+	notStruct   structKind = iota
+	noFields               // struct without exported fields, ie: time.Time
+	generated              // struct type generated by this Generator
+	otherStruct            // any other struct, handled by the structs package
+)
+
+func (g *Generator) structKind(t types.Type) structKind {
+	st, ok := t.Underlying().(*types.Struct)
+	if !ok {
+		return notStruct
+	}
+	if n, ok := t.(*types.Named); ok && g.types[n.Obj()] {
+		return generated
+	}
+	if fields, _ := g.fields(st); len(fields) == 0 {
+		return noFields
+	}
+	return otherStruct
+}
+
+// mapOf writes the conversion of the struct expr to dst, like the structs nested,
+// which keeps the value itself if the map is empty.
+func (g *Generator) mapOf(dst, expr string, t types.Type) {
+	switch g.structKind(t) {
+	case generated:
+		g.printf("if mm := %s.ToMap(); len(mm) > 0 {\n%s = mm\n}\n", expr, dst)
+	case otherStruct:
+		g.printf("if mm := %s.MapWithTag(%s, %q); len(mm) > 0 {\n%s = mm\n}\n", g.use(structsPkgPath, "structs"), expr, g.tagName, dst)
+	}
+}
+
+// isNested reports whether nested converts a value of type t.
+func (g *Generator) isNested(t types.Type) bool {
+	switch u := t.Underlying().(type) {
+	case *types.Struct:
+		return g.structKind(t) == generated || g.structKind(t) == otherStruct
+	case *types.Pointer:
+		return g.structKind(u.Elem()) == generated || g.structKind(u.Elem()) == otherStruct
+	case *types.Interface:
+		return true
+	case *types.Map:
+		elem := u.Elem()
+		if p, ok := elem.Underlying().(*types.Pointer); ok {
+			elem = p.Elem()
+		}
+		if s, ok := elem.Underlying().(*types.Slice); ok {
+			elem = s.Elem()
+		}
+		return g.structKind(elem) != notStruct
+	case *types.Slice:
+		return g.isNestedElem(u.Elem())
+	case *types.Array:
+		return g.isNestedElem(u.Elem())
+	}
+	return false
+}
+
+func (g *Generator) isNestedElem(elem types.Type) bool {
+	if p, ok := elem.Underlying().(*types.Pointer); ok {
+		elem = p.Elem()
+	}
+	return g.structKind(elem) != notStruct
+}
+
+// nested writes the conversion of expr of type t into dst, like the structs nested.
+func (g *Generator) nested(dst, expr string, t types.Type) {
+	g.printf("%s = %s\n", dst, expr)
+	switch u := t.Underlying().(type) {
+	case *types.Struct:
+		g.mapOf(dst, expr, t)
+	case *types.Pointer:
+		if g.structKind(u.Elem()) == generated || g.structKind(u.Elem()) == otherStruct {
+			g.printf("if %s != nil {\n", expr)
+			g.mapOf(dst, expr, u.Elem())
+			g.printf("}\n")
+		}
+	case *types.Interface:
+		structs := g.use(structsPkgPath, "structs")
+		g.printf("if %s.IsStruct(%s) {\n", structs, expr)
+		g.printf("if mm := %s.MapWithTag(%s, %q); len(mm) > 0 {\n%s = mm\n}\n", structs, expr, g.tagName, dst)
+		g.printf("} else {\n%s = %s.RedactedWithTag(%s, %q)\n}\n", dst, structs, expr, g.tagName)
+	case *types.Map:
+		elem := u.Elem()
+		if p, ok := elem.Underlying().(*types.Pointer); ok {
+			elem = p.Elem()
+		}
+		isStruct := g.structKind(elem) != notStruct
+		if s, ok := elem.Underlying().(*types.Slice); ok && g.structKind(s.Elem()) != notStruct {
+			isStruct = true
+		}
+		if !isStruct {
+			return
+		}
+		m := g.temp("m")
+		g.printf("%s := make(map[string]interface{}, len(%s))\n", m, expr)
+		g.printf("for k, e := range %s {\n", expr)
+		key := g.keyString("k", u.Key())
+		e := g.temp("e")
+		g.printf("var %s interface{}\n", e)
+		g.nested(e, "e", u.Elem())
+		g.printf("%s[%s] = %s\n", m, key, e)
+		g.printf("}\n")
+		g.printf("%s = %s\n", dst, m)
+	case *types.Slice:
+		g.nestedSlice(dst, expr, u.Elem())
+	case *types.Array:
+		g.nestedSlice(dst, expr, u.Elem())
+	}
+}
+
+func (g *Generator) nestedSlice(dst, expr string, elem types.Type) {
+	isStruct := g.structKind(elem) != notStruct
+	if p, ok := elem.Underlying().(*types.Pointer); ok && g.structKind(p.Elem()) != notStruct {
+		isStruct = true
+	}
+	if !isStruct {
+		return
+	}
+	s := g.temp("s")
+	g.printf("%s := make([]interface{}, len(%s))\n", s, expr)
+	g.printf("for i := range %s {\n", expr)
+	g.nested(s+"[i]", expr+"[i]", elem)
+	g.printf("}\n")
+	g.printf("%s = %s\n", dst, s)
+}
+
+// keyString returns the string expression of the map key expr, like the
+// structs MapKey, writing the conversion of keys of other than string kinds,
+// which formats unsupported keys by fmt.Sprint the same as Map.
+func (g *Generator) keyString(expr string, t types.Type) string {
+	if b, ok := t.Underlying().(*types.Basic); ok && b.Info()&types.IsString != 0 {
+		return "string(" + expr + ")"
+	}
+	key := g.temp("key")
+	g.printf("%s, err := %s.MapKey(%s)\nif err != nil {\n%s = %s.Sprint(%s)\n}\n",
+		key, g.use(structsPkgPath, "structs"), expr, key, g.use("fmt", "fmt"), expr)
+	return key
+}
+
+func (g *Generator) genValuesField(f *field) {
+	expr := "x." + f.goName
+	if f.has("omitempty") {
+		if cond := g.emptyValue(expr, f.typ); cond != "" {
+			g.printf("if %s {\n", negate(cond))
+			defer g.printf("}\n")
+		}
+	}
+	if f.has("omitzero") {
+		g.printf("if !%s.IsZeroValue(%s) {\n", g.use(structsPkgPath, "structs"), expr)
+		defer g.printf("}\n")
+	}
+	if mode, ok := f.redactMode(); ok {
+		structs := g.use(structsPkgPath, "structs")
+		g.printf("out = append(out, %s.Mask(%s, %s.%s))\n", structs, expr, structs, mode)
+		return
+	}
+	if format, ok := f.value("format"); ok {
+		g.printf("out = append(out, %s)\n", g.formatValue(expr, f.typ, format))
+		return
+	}
+	if f.has("string") {
+		if !g.toString(expr, f.typ, func(str string) { g.printf("out = append(out, %s)\n", str) }) {
+			return
+		}
+		defer g.printf("}\n")
+	}
+	if f.has("omitnested") {
+		g.printf("out = append(out, %s)\n", expr)
+		return
+	}
+	switch u := f.typ.Underlying().(type) {
+	case *types.Struct:
+		g.valuesOf(expr, f.typ)
+		return
+	case *types.Pointer:
+		switch g.structKind(u.Elem()) {
+		case generated, otherStruct:
+			g.printf("if %s != nil {\n", expr)
+			g.valuesOf(expr, u.Elem())
+			g.printf("} else {\nout = append(out, %s)\n}\n", expr)
+			return
+		case noFields:
+			g.printf("if %s == nil {\nout = append(out, %s)\n}\n", expr, expr)
+			return
+		}
+	case *types.Interface:
+		structs := g.use(structsPkgPath, "structs")
+		g.printf("if %s.IsStruct(%s) {\nout = append(out, %s.ValuesWithTag(%s, %q)...)\n} else {\nout = append(out, %s.RedactedWithTag(%s, %q))\n}\n",
+			structs, expr, structs, expr, g.tagName, structs, expr, g.tagName)
+		return
+	}
+	if g.isSensitive(f.typ, make(map[types.Type]bool)) {
+		structs := g.use(structsPkgPath, "structs")
+		g.printf("out = append(out, %s.RedactedWithTag(%s, %q))\n", structs, expr, g.tagName)
+		return
+	}
+	g.printf("out = append(out, %s)\n", expr)
+}
+
+// isSensitive reports whether the values of t may hold sensitive fields, like
+// the structs typeSensitivity, interfaces may hold anything.
+func (g *Generator) isSensitive(t types.Type, seen map[types.Type]bool) bool {
+	if seen[t] {
+		return false
+	}
+	seen[t] = true
+	switch u := t.Underlying().(type) {
+	case *types.Struct:
+		for i := 0; i < u.NumFields(); i++ {
+			v := u.Field(i)
+			if !v.Exported() {
+				continue
+			}
+			tag := reflect.StructTag(u.Tag(i)).Get(g.tagName)
+			_, opts := structs.ParseTag(tag)
+			f := &field{opts: opts}
+			if _, ok := f.redactMode(); ok {
+				return true
+			}
+			if g.isSensitive(v.Type(), seen) {
+				return true
+			}
+		}
+	case *types.Pointer:
+		return g.isSensitive(u.Elem(), seen)
+	case *types.Slice:
+		return g.isSensitive(u.Elem(), seen)
+	case *types.Array:
+		return g.isSensitive(u.Elem(), seen)
+	case *types.Map:
+		return g.isSensitive(u.Elem(), seen)
+	case *types.Interface:
+		return true
+	}
+	return false
+}
+
+// valuesOf appends the values of the struct expr of type t, like the
+// structs Values.
+func (g *Generator) valuesOf(expr string, t types.Type) {
+	switch g.structKind(t) {
+	case generated:
+		g.printf("out = append(out, %s.Values()...)\n", expr)
+	case otherStruct:
+		g.printf("out = append(out, %s.ValuesWithTag(%s, %q)...)\n", g.use(structsPkgPath, "structs"), expr, g.tagName)
+	}
+}
+
+func (g *Generator) genIsZeroField(f *field) {
+	expr := "x." + f.goName
+	if !f.has("omitnested") {
+		switch u := f.typ.Underlying().(type) {
+		case *types.Struct:
+			if cond := g.isZeroOf(expr, f.typ); cond != "" {
+				g.printf("if !%s {\nreturn false\n}\n", cond)
+			}
+			return
+		case *types.Pointer:
+			if g.structKind(u.Elem()) != notStruct {
+				if cond := g.isZeroOf(expr, u.Elem()); cond != "" {
+					g.printf("if %s != nil && !%s {\nreturn false\n}\n", expr, cond)
+				}
+				return
+			}
+		case *types.Interface:
+			structs := g.use(structsPkgPath, "structs")
+			g.printf("if %s.IsStruct(%s) {\nif !%s.IsZeroWithTag(%s, %q) {\nreturn false\n}\n} else if %s != nil {\nreturn false\n}\n",
+				structs, expr, structs, expr, g.tagName, expr)
+			return
+		}
+	}
+	g.printf("if %s {\nreturn false\n}\n", negate(g.emptyWithAll(expr, f.typ)))
+}
+
+// isZeroOf returns the call of IsZero of the struct expr of type t, or an
+// empty string if it is always zero, as a struct without exported fields.
+func (g *Generator) isZeroOf(expr string, t types.Type) string {
+	switch g.structKind(t) {
+	case generated:
+		return expr + ".IsZero()"
+	case otherStruct:
+		return fmt.Sprintf("%s.IsZeroWithTag(%s, %q)", g.use(structsPkgPath, "structs"), expr, g.tagName)
+	}
+	return ""
+}
+
+func (g *Generator) genFromMapField(f *field) {
+	expr := "x." + f.goName
+	if f.has("flatten") {
+		if p, ok := f.typ.Underlying().(*types.Pointer); ok && g.structKind(p.Elem()) == generated {
+			// the pointer is set only if the struct is not zero, like Map
+			// outputs a nil pointer as is.
+			ptr := g.temp("ptr")
+			g.printf("%s := %s\nif %s == nil {\n%s = new(%s)\n}\n", ptr, expr, ptr, ptr, g.typeString(p.Elem()))
+			g.genFromMapFlatten(ptr, f)
+			g.printf("if %s != nil || !%s.IsZero() {\n%s = %s\n}\n", expr, ptr, expr, ptr)
+			return
+		}
+		if g.structKind(f.typ) == generated {
+			g.genFromMapFlatten(expr, f)
+			return
+		}
+	}
+
+	if it, ok := f.typ.Underlying().(*types.Interface); ok && it.Empty() {
+		g.printf("if v, ok := m[%q]; ok {\n%s = v\n}\n", f.name, expr)
+		return
+	}
+	typ := g.typeString(f.typ)
+	g.printf("if v, ok := m[%q]; ok {\n", f.name)
+	g.printf("switch vv := v.(type) {\n")
+	g.printf("case %s:\n%s = vv\n", typ, expr)
+	if f.has("string") {
+		g.genParseString(expr, f)
+	}
+	if format, ok := f.value("format"); ok {
+		g.genParseTime(expr, f, format)
+	}
+	switch u := f.typ.Underlying().(type) {
+	case *types.Struct:
+		if g.structKind(f.typ) == generated {
+			g.printf("case map[string]interface{}:\nif err := %s.FromMap(vv); err != nil {\nreturn err\n}\n", expr)
+		}
+	case *types.Pointer:
+		if g.structKind(u.Elem()) == generated {
+			g.printf("case %s:\n%s = &vv\n", g.typeString(u.Elem()), expr)
+			g.printf("case map[string]interface{}:\nif %s == nil {\n%s = new(%s)\n}\nif err := %s.FromMap(vv); err != nil {\nreturn err\n}\n",
+				expr, expr, g.typeString(u.Elem()), expr)
+		}
+	}
+	switch f.typ.Underlying().(type) {
+	case *types.Pointer, *types.Slice, *types.Map, *types.Chan, *types.Signature, *types.Interface:
+		g.printf("case nil:\n%s = nil\n", expr)
+	}
+	g.printf("default:\nreturn %s.Errorf(\"structs: field %s: cannot assign %%T\", v)\n", g.use("fmt", "fmt"), f.goName)
+	g.printf("}\n}\n")
+}
+
+// genFromMapFlatten writes the FromMap call of the flattened struct expr of
+// field f, with the keys of m without the prefix of the option "prefix".
+func (g *Generator) genFromMapFlatten(expr string, f *field) {
+	prefix, _ := f.value("prefix")
+	if prefix == "" {
+		g.printf("if err := %s.FromMap(m); err != nil {\nreturn err\n}\n", expr)
+		return
+	}
+	sub := g.temp("sub")
+	g.printf("%s := make(map[string]interface{})\nfor k, v := range m {\nif %s.HasPrefix(k, %q) {\n%s[k[%d:]] = v\n}\n}\n",
+		sub, g.use("strings", "strings"), prefix, sub, len(prefix))
+	g.printf("if err := %s.FromMap(%s); err != nil {\nreturn err\n}\n", expr, sub)
+}
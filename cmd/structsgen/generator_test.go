@@ -0,0 +1,86 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+func TestGenerate_Golden(t *testing.T) {
+	dir := filepath.Join("internal", "sample")
+	golden := filepath.Join(dir, "sample_structs.go")
+
+	pkg, got, err := generate(dir, nil, "map")
+	require.NoError(t, err)
+	require.Equal(t, "sample", pkg)
+
+	if *update {
+		require.NoError(t, os.WriteFile(golden, got, 0o644)) // nolint: gosec
+	}
+	want, err := os.ReadFile(golden)
+	require.NoError(t, err)
+	require.Equal(t, string(want), string(got))
+}
+
+func TestGenerate_Types(t *testing.T) {
+	dir := filepath.Join("internal", "sample")
+
+	_, got, err := generate(dir, []string{"Meta"}, "json")
+	require.NoError(t, err)
+	require.Contains(t, string(got), "func (x Meta) ToMap() map[string]interface{} {")
+	require.NotContains(t, string(got), "func (x User) ToMap()")
+
+	_, _, err = generate(dir, []string{"Missing"}, "map")
+	require.Error(t, err)
+
+	_, _, err = generate(dir, []string{"Level"}, "map")
+	require.Error(t, err)
+
+	_, _, err = generate(".", nil, "map")
+	require.Error(t, err)
+}
+
+func TestGenerate_TypeErrors(t *testing.T) {
+	write := func(t *testing.T, src string) string {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "a.go"), []byte(src), 0o644)) // nolint: gosec
+		return dir
+	}
+	const typ = `package a
+
+//structs:generate
+type T struct {
+	A int ` + "`map:\"a\"`" + `
+}
+`
+
+	// the generated methods may be used before they are generated
+	dir := write(t, typ+`
+type Mapper interface{ ToMap() map[string]interface{} }
+
+var _ Mapper = T{}
+
+func use(x *T) bool { return x.IsZero() && x.FromMap(x.ToMap()) == nil }
+`)
+	_, got, err := generate(dir, nil, "map")
+	require.NoError(t, err)
+	require.Contains(t, string(got), "func (x T) ToMap() map[string]interface{} {")
+
+	// other type errors fail
+	dir = write(t, typ+`
+func bad() int { return "x" }
+`)
+	_, _, err = generate(dir, nil, "map")
+	require.Error(t, err)
+
+	dir = write(t, typ+`
+func use(x T) int { return x.Missing() }
+`)
+	_, _, err = generate(dir, nil, "map")
+	require.Error(t, err)
+}
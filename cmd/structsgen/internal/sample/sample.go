@@ -0,0 +1,94 @@
+// Package sample contains the struct types used to verify that the code
+// generated by structsgen matches the structs package.
+package sample
+
+import (
+	"database/sql"
+	"time"
+)
+
+//go:generate go run github.com/things-go/structs/cmd/structsgen
+
+// Level has a String method, used by the "string" option.
+type Level string
+
+func (l Level) String() string {
+	return "level-" + string(l)
+}
+
+// Contact is flattened through a pointer.
+//
+//structs:generate
+type Contact struct {
+	Phone string `map:"phone"`
+}
+
+// Address is a nested struct.
+//
+//structs:generate
+type Address struct {
+	City string `map:"city"`
+	Zip  string `map:"zip,omitempty"`
+}
+
+// Extra has only omitempty fields, so its map is empty when it is zero.
+//
+//structs:generate
+type Extra struct {
+	Note string `map:"note,omitempty"`
+}
+
+// Secret has a sensitive field, which is masked in interfaces as well.
+type Secret struct {
+	Token string `map:"token,redact"`
+}
+
+// Meta has no generated methods, it is handled by the structs package.
+type Meta struct {
+	Version int    `map:"version"`
+	Note    string `map:"note,omitempty"`
+}
+
+// User covers the tag options of the structs package.
+//
+//structs:generate
+type User struct {
+	ID        int64                    `map:"id"`
+	Name      string                   `map:"name"`
+	Email     string                   `map:"email,omitempty"`
+	Age       *int                     `map:"age,omitempty"`
+	Score     float32                  `map:"score,string"`
+	Count     *uint                    `map:"count,string"`
+	Level     Level                    `map:"level,string"`
+	Active    bool                     `map:"active"`
+	Tags      []string                 `map:"tags,omitempty"`
+	Props     map[string]interface{}   `map:"props"`
+	Address   Address                  `map:"address"`
+	Home      *Address                 `map:"home"`
+	Work      Address                  `map:",flatten"`
+	Contact   *Contact                 `map:",flatten"`
+	Raw       Address                  `map:"raw,omitnested"`
+	Meta      Meta                     `map:"meta"`
+	Previous  []Address                `map:"previous"`
+	Others    []*Address               `map:"others"`
+	ByName    map[string]Address       `map:"by_name"`
+	ByID      map[int]*Address         `map:"by_id"`
+	ByDay     map[time.Weekday]Address `map:"by_day"`
+	CreatedAt time.Time                `map:"created_at"`
+	UpdatedAt *time.Time               `map:"updated_at"`
+	Any       interface{}              `map:"any"`
+	Password  string                   `map:"password,redact"`
+	Card      string                   `map:"card,sensitive=last4"`
+	Born      time.Time                `map:"born,format=2006-01-02"`
+	Seen      *time.Time               `map:"seen,format='Jan 2, 2006'"`
+	Price     float64                  `map:"price,format=%.2f"`
+	Office    Address                  `map:",flatten,prefix=office_"`
+	Expires   time.Time                `map:"expires,format=unix"`
+	Timeout   time.Duration            `map:"timeout,format=string"`
+	Wait      *time.Duration           `map:"wait,format=nanos"`
+	Deadline  time.Time                `map:"deadline,omitzero"`
+	Nickname  sql.NullString           `map:"nickname,omitzero"`
+	Extra     Extra                    `map:"extra,flatten"`
+	Ignored   string                   `map:"-"`
+	secret    string
+}
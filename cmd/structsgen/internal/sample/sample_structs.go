@@ -0,0 +1,906 @@
+// Code generated by structsgen. DO NOT EDIT.
+
+package sample
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/things-go/structs"
+)
+
+// ToMap converts Contact to a map[string]interface{}, the same as structs.Map.
+func (x Contact) ToMap() map[string]interface{} {
+	out := make(map[string]interface{}, 1)
+	out["phone"] = x.Phone
+	return out
+}
+
+// Values converts the Contact field values to a []interface{}, the same as structs.Values.
+func (x Contact) Values() []interface{} {
+	out := make([]interface{}, 0, 1)
+	out = append(out, x.Phone)
+	return out
+}
+
+// Names returns the Contact field names, the same as structs.Names.
+func (x Contact) Names() []string {
+	return []string{"Phone"}
+}
+
+// IsZero returns true if all fields of Contact are zero values, the same as structs.IsZero.
+func (x Contact) IsZero() bool {
+	if len(x.Phone) != 0 {
+		return false
+	}
+	return true
+}
+
+// FromMap sets the fields of Contact from m, which is keyed the same as the output of ToMap.
+// A value must have the type of the field, or be a map[string]interface{} for a
+// field of generated struct type. Missing keys leave the field untouched.
+func (x *Contact) FromMap(m map[string]interface{}) error {
+	if v, ok := m["phone"]; ok {
+		switch vv := v.(type) {
+		case string:
+			x.Phone = vv
+		default:
+			return fmt.Errorf("structs: field Phone: cannot assign %T", v)
+		}
+	}
+	return nil
+}
+
+// ToMap converts Address to a map[string]interface{}, the same as structs.Map.
+func (x Address) ToMap() map[string]interface{} {
+	out := make(map[string]interface{}, 2)
+	out["city"] = x.City
+	if len(x.Zip) != 0 {
+		out["zip"] = x.Zip
+	}
+	return out
+}
+
+// Values converts the Address field values to a []interface{}, the same as structs.Values.
+func (x Address) Values() []interface{} {
+	out := make([]interface{}, 0, 2)
+	out = append(out, x.City)
+	if len(x.Zip) != 0 {
+		out = append(out, x.Zip)
+	}
+	return out
+}
+
+// Names returns the Address field names, the same as structs.Names.
+func (x Address) Names() []string {
+	return []string{"City", "Zip"}
+}
+
+// IsZero returns true if all fields of Address are zero values, the same as structs.IsZero.
+func (x Address) IsZero() bool {
+	if len(x.City) != 0 {
+		return false
+	}
+	if len(x.Zip) != 0 {
+		return false
+	}
+	return true
+}
+
+// FromMap sets the fields of Address from m, which is keyed the same as the output of ToMap.
+// A value must have the type of the field, or be a map[string]interface{} for a
+// field of generated struct type. Missing keys leave the field untouched.
+func (x *Address) FromMap(m map[string]interface{}) error {
+	if v, ok := m["city"]; ok {
+		switch vv := v.(type) {
+		case string:
+			x.City = vv
+		default:
+			return fmt.Errorf("structs: field City: cannot assign %T", v)
+		}
+	}
+	if v, ok := m["zip"]; ok {
+		switch vv := v.(type) {
+		case string:
+			x.Zip = vv
+		default:
+			return fmt.Errorf("structs: field Zip: cannot assign %T", v)
+		}
+	}
+	return nil
+}
+
+// ToMap converts Extra to a map[string]interface{}, the same as structs.Map.
+func (x Extra) ToMap() map[string]interface{} {
+	out := make(map[string]interface{}, 1)
+	if len(x.Note) != 0 {
+		out["note"] = x.Note
+	}
+	return out
+}
+
+// Values converts the Extra field values to a []interface{}, the same as structs.Values.
+func (x Extra) Values() []interface{} {
+	out := make([]interface{}, 0, 1)
+	if len(x.Note) != 0 {
+		out = append(out, x.Note)
+	}
+	return out
+}
+
+// Names returns the Extra field names, the same as structs.Names.
+func (x Extra) Names() []string {
+	return []string{"Note"}
+}
+
+// IsZero returns true if all fields of Extra are zero values, the same as structs.IsZero.
+func (x Extra) IsZero() bool {
+	if len(x.Note) != 0 {
+		return false
+	}
+	return true
+}
+
+// FromMap sets the fields of Extra from m, which is keyed the same as the output of ToMap.
+// A value must have the type of the field, or be a map[string]interface{} for a
+// field of generated struct type. Missing keys leave the field untouched.
+func (x *Extra) FromMap(m map[string]interface{}) error {
+	if v, ok := m["note"]; ok {
+		switch vv := v.(type) {
+		case string:
+			x.Note = vv
+		default:
+			return fmt.Errorf("structs: field Note: cannot assign %T", v)
+		}
+	}
+	return nil
+}
+
+// ToMap converts User to a map[string]interface{}, the same as structs.Map.
+func (x User) ToMap() map[string]interface{} {
+	out := make(map[string]interface{}, 36)
+	out["id"] = x.ID
+	out["name"] = x.Name
+	if len(x.Email) != 0 {
+		out["email"] = x.Email
+	}
+	if x.Age != nil {
+		out["age"] = x.Age
+	}
+	out["score"] = strconv.FormatFloat(float64(x.Score), 'f', -1, 64)
+	if x.Count != nil {
+		out["count"] = strconv.FormatUint(uint64(*x.Count), 10)
+	} else if s, ok := interface{}(x.Count).(fmt.Stringer); ok {
+		out["count"] = s.String()
+	} else {
+		out["count"] = x.Count
+	}
+	out["level"] = x.Level.String()
+	out["active"] = x.Active
+	if len(x.Tags) != 0 {
+		out["tags"] = x.Tags
+	}
+	out["props"] = structs.RedactedWithTag(x.Props, "map")
+	var v1 interface{}
+	v1 = x.Address
+	if mm := x.Address.ToMap(); len(mm) > 0 {
+		v1 = mm
+	}
+	out["address"] = v1
+	var v2 interface{}
+	v2 = x.Home
+	if x.Home != nil {
+		if mm := x.Home.ToMap(); len(mm) > 0 {
+			v2 = mm
+		}
+	}
+	out["home"] = v2
+	var v3 interface{}
+	v3 = x.Work
+	if mm := x.Work.ToMap(); len(mm) > 0 {
+		v3 = mm
+	}
+	if mm, ok := v3.(map[string]interface{}); ok {
+		for k, e := range mm {
+			out[k] = e
+		}
+	} else {
+		out["Work"] = v3
+	}
+	var v4 interface{}
+	v4 = x.Contact
+	if x.Contact != nil {
+		if mm := x.Contact.ToMap(); len(mm) > 0 {
+			v4 = mm
+		}
+	}
+	if mm, ok := v4.(map[string]interface{}); ok {
+		for k, e := range mm {
+			out[k] = e
+		}
+	} else {
+		out["Contact"] = v4
+	}
+	out["raw"] = x.Raw
+	var v5 interface{}
+	v5 = x.Meta
+	if mm := structs.MapWithTag(x.Meta, "map"); len(mm) > 0 {
+		v5 = mm
+	}
+	out["meta"] = v5
+	var v6 interface{}
+	v6 = x.Previous
+	s7 := make([]interface{}, len(x.Previous))
+	for i := range x.Previous {
+		s7[i] = x.Previous[i]
+		if mm := x.Previous[i].ToMap(); len(mm) > 0 {
+			s7[i] = mm
+		}
+	}
+	v6 = s7
+	out["previous"] = v6
+	var v8 interface{}
+	v8 = x.Others
+	s9 := make([]interface{}, len(x.Others))
+	for i := range x.Others {
+		s9[i] = x.Others[i]
+		if x.Others[i] != nil {
+			if mm := x.Others[i].ToMap(); len(mm) > 0 {
+				s9[i] = mm
+			}
+		}
+	}
+	v8 = s9
+	out["others"] = v8
+	var v10 interface{}
+	v10 = x.ByName
+	m11 := make(map[string]interface{}, len(x.ByName))
+	for k, e := range x.ByName {
+		var e12 interface{}
+		e12 = e
+		if mm := e.ToMap(); len(mm) > 0 {
+			e12 = mm
+		}
+		m11[string(k)] = e12
+	}
+	v10 = m11
+	out["by_name"] = v10
+	var v13 interface{}
+	v13 = x.ByID
+	m14 := make(map[string]interface{}, len(x.ByID))
+	for k, e := range x.ByID {
+		key15, err := structs.MapKey(k)
+		if err != nil {
+			key15 = fmt.Sprint(k)
+		}
+		var e16 interface{}
+		e16 = e
+		if e != nil {
+			if mm := e.ToMap(); len(mm) > 0 {
+				e16 = mm
+			}
+		}
+		m14[key15] = e16
+	}
+	v13 = m14
+	out["by_id"] = v13
+	var v17 interface{}
+	v17 = x.ByDay
+	m18 := make(map[string]interface{}, len(x.ByDay))
+	for k, e := range x.ByDay {
+		key19, err := structs.MapKey(k)
+		if err != nil {
+			key19 = fmt.Sprint(k)
+		}
+		var e20 interface{}
+		e20 = e
+		if mm := e.ToMap(); len(mm) > 0 {
+			e20 = mm
+		}
+		m18[key19] = e20
+	}
+	v17 = m18
+	out["by_day"] = v17
+	out["created_at"] = x.CreatedAt
+	out["updated_at"] = x.UpdatedAt
+	var v21 interface{}
+	v21 = x.Any
+	if structs.IsStruct(x.Any) {
+		if mm := structs.MapWithTag(x.Any, "map"); len(mm) > 0 {
+			v21 = mm
+		}
+	} else {
+		v21 = structs.RedactedWithTag(x.Any, "map")
+	}
+	out["any"] = v21
+	out["password"] = structs.Mask(x.Password, structs.RedactDefault)
+	out["card"] = structs.Mask(x.Card, structs.RedactLast4)
+	out["born"] = x.Born.Format("2006-01-02")
+	out["seen"] = structs.FormatValue(x.Seen, "Jan 2, 2006")
+	out["price"] = fmt.Sprintf("%.2f", x.Price)
+	var v22 interface{}
+	v22 = x.Office
+	if mm := x.Office.ToMap(); len(mm) > 0 {
+		v22 = mm
+	}
+	if mm, ok := v22.(map[string]interface{}); ok {
+		for k, e := range mm {
+			out["office_"+k] = e
+		}
+	} else {
+		out["Office"] = v22
+	}
+	out["expires"] = x.Expires.Unix()
+	out["timeout"] = x.Timeout.String()
+	out["wait"] = structs.FormatValue(x.Wait, "nanos")
+	if !structs.IsZeroValue(x.Deadline) {
+		out["deadline"] = x.Deadline
+	}
+	if !structs.IsZeroValue(x.Nickname) {
+		var v23 interface{}
+		v23 = x.Nickname
+		if mm := structs.MapWithTag(x.Nickname, "map"); len(mm) > 0 {
+			v23 = mm
+		}
+		out["nickname"] = v23
+	}
+	var v24 interface{}
+	v24 = x.Extra
+	if mm := x.Extra.ToMap(); len(mm) > 0 {
+		v24 = mm
+	}
+	if mm, ok := v24.(map[string]interface{}); ok {
+		for k, e := range mm {
+			out[k] = e
+		}
+	} else {
+		out["extra"] = v24
+	}
+	return out
+}
+
+// Values converts the User field values to a []interface{}, the same as structs.Values.
+func (x User) Values() []interface{} {
+	out := make([]interface{}, 0, 36)
+	out = append(out, x.ID)
+	out = append(out, x.Name)
+	if len(x.Email) != 0 {
+		out = append(out, x.Email)
+	}
+	if x.Age != nil {
+		out = append(out, x.Age)
+	}
+	out = append(out, strconv.FormatFloat(float64(x.Score), 'f', -1, 64))
+	if x.Count != nil {
+		out = append(out, strconv.FormatUint(uint64(*x.Count), 10))
+	} else if s, ok := interface{}(x.Count).(fmt.Stringer); ok {
+		out = append(out, s.String())
+	} else {
+		out = append(out, x.Count)
+	}
+	out = append(out, x.Level.String())
+	out = append(out, x.Active)
+	if len(x.Tags) != 0 {
+		out = append(out, x.Tags)
+	}
+	out = append(out, structs.RedactedWithTag(x.Props, "map"))
+	out = append(out, x.Address.Values()...)
+	if x.Home != nil {
+		out = append(out, x.Home.Values()...)
+	} else {
+		out = append(out, x.Home)
+	}
+	out = append(out, x.Work.Values()...)
+	if x.Contact != nil {
+		out = append(out, x.Contact.Values()...)
+	} else {
+		out = append(out, x.Contact)
+	}
+	out = append(out, x.Raw)
+	out = append(out, structs.ValuesWithTag(x.Meta, "map")...)
+	out = append(out, x.Previous)
+	out = append(out, x.Others)
+	out = append(out, x.ByName)
+	out = append(out, x.ByID)
+	out = append(out, x.ByDay)
+	if x.UpdatedAt == nil {
+		out = append(out, x.UpdatedAt)
+	}
+	if structs.IsStruct(x.Any) {
+		out = append(out, structs.ValuesWithTag(x.Any, "map")...)
+	} else {
+		out = append(out, structs.RedactedWithTag(x.Any, "map"))
+	}
+	out = append(out, structs.Mask(x.Password, structs.RedactDefault))
+	out = append(out, structs.Mask(x.Card, structs.RedactLast4))
+	out = append(out, x.Born.Format("2006-01-02"))
+	out = append(out, structs.FormatValue(x.Seen, "Jan 2, 2006"))
+	out = append(out, fmt.Sprintf("%.2f", x.Price))
+	out = append(out, x.Office.Values()...)
+	out = append(out, x.Expires.Unix())
+	out = append(out, x.Timeout.String())
+	out = append(out, structs.FormatValue(x.Wait, "nanos"))
+	if !structs.IsZeroValue(x.Deadline) {
+	}
+	if !structs.IsZeroValue(x.Nickname) {
+		out = append(out, structs.ValuesWithTag(x.Nickname, "map")...)
+	}
+	out = append(out, x.Extra.Values()...)
+	return out
+}
+
+// Names returns the User field names, the same as structs.Names.
+func (x User) Names() []string {
+	return []string{"ID", "Name", "Email", "Age", "Score", "Count", "Level", "Active", "Tags", "Props", "Address", "Home", "Work", "Contact", "Raw", "Meta", "Previous", "Others", "ByName", "ByID", "ByDay", "CreatedAt", "UpdatedAt", "Any", "Password", "Card", "Born", "Seen", "Price", "Office", "Expires", "Timeout", "Wait", "Deadline", "Nickname", "Extra", "secret"}
+}
+
+// IsZero returns true if all fields of User are zero values, the same as structs.IsZero.
+func (x User) IsZero() bool {
+	if x.ID != 0 {
+		return false
+	}
+	if len(x.Name) != 0 {
+		return false
+	}
+	if len(x.Email) != 0 {
+		return false
+	}
+	if !(x.Age == nil || *x.Age == 0) {
+		return false
+	}
+	if x.Score != 0 {
+		return false
+	}
+	if !(x.Count == nil || *x.Count == 0) {
+		return false
+	}
+	if len(x.Level) != 0 {
+		return false
+	}
+	if x.Active {
+		return false
+	}
+	if len(x.Tags) != 0 {
+		return false
+	}
+	if len(x.Props) != 0 {
+		return false
+	}
+	if !x.Address.IsZero() {
+		return false
+	}
+	if x.Home != nil && !x.Home.IsZero() {
+		return false
+	}
+	if !x.Work.IsZero() {
+		return false
+	}
+	if x.Contact != nil && !x.Contact.IsZero() {
+		return false
+	}
+	if x.Raw != (Address{}) {
+		return false
+	}
+	if !structs.IsZeroWithTag(x.Meta, "map") {
+		return false
+	}
+	if len(x.Previous) != 0 {
+		return false
+	}
+	if len(x.Others) != 0 {
+		return false
+	}
+	if len(x.ByName) != 0 {
+		return false
+	}
+	if len(x.ByID) != 0 {
+		return false
+	}
+	if len(x.ByDay) != 0 {
+		return false
+	}
+	if structs.IsStruct(x.Any) {
+		if !structs.IsZeroWithTag(x.Any, "map") {
+			return false
+		}
+	} else if x.Any != nil {
+		return false
+	}
+	if len(x.Password) != 0 {
+		return false
+	}
+	if len(x.Card) != 0 {
+		return false
+	}
+	if x.Price != 0 {
+		return false
+	}
+	if !x.Office.IsZero() {
+		return false
+	}
+	if x.Timeout != 0 {
+		return false
+	}
+	if !(x.Wait == nil || *x.Wait == 0) {
+		return false
+	}
+	if !structs.IsZeroWithTag(x.Nickname, "map") {
+		return false
+	}
+	if !x.Extra.IsZero() {
+		return false
+	}
+	return true
+}
+
+// FromMap sets the fields of User from m, which is keyed the same as the output of ToMap.
+// A value must have the type of the field, or be a map[string]interface{} for a
+// field of generated struct type. Missing keys leave the field untouched.
+func (x *User) FromMap(m map[string]interface{}) error {
+	if v, ok := m["id"]; ok {
+		switch vv := v.(type) {
+		case int64:
+			x.ID = vv
+		default:
+			return fmt.Errorf("structs: field ID: cannot assign %T", v)
+		}
+	}
+	if v, ok := m["name"]; ok {
+		switch vv := v.(type) {
+		case string:
+			x.Name = vv
+		default:
+			return fmt.Errorf("structs: field Name: cannot assign %T", v)
+		}
+	}
+	if v, ok := m["email"]; ok {
+		switch vv := v.(type) {
+		case string:
+			x.Email = vv
+		default:
+			return fmt.Errorf("structs: field Email: cannot assign %T", v)
+		}
+	}
+	if v, ok := m["age"]; ok {
+		switch vv := v.(type) {
+		case *int:
+			x.Age = vv
+		case nil:
+			x.Age = nil
+		default:
+			return fmt.Errorf("structs: field Age: cannot assign %T", v)
+		}
+	}
+	if v, ok := m["score"]; ok {
+		switch vv := v.(type) {
+		case float32:
+			x.Score = vv
+		case string:
+			n, err := strconv.ParseFloat(vv, 32)
+			if err != nil {
+				return fmt.Errorf("structs: field Score: %w", err)
+			}
+			x.Score = float32(n)
+		default:
+			return fmt.Errorf("structs: field Score: cannot assign %T", v)
+		}
+	}
+	if v, ok := m["count"]; ok {
+		switch vv := v.(type) {
+		case *uint:
+			x.Count = vv
+		case string:
+			n, err := strconv.ParseUint(vv, 10, 64)
+			if err != nil {
+				return fmt.Errorf("structs: field Count: %w", err)
+			}
+			val := uint(n)
+			x.Count = &val
+		case nil:
+			x.Count = nil
+		default:
+			return fmt.Errorf("structs: field Count: cannot assign %T", v)
+		}
+	}
+	if v, ok := m["level"]; ok {
+		switch vv := v.(type) {
+		case Level:
+			x.Level = vv
+		default:
+			return fmt.Errorf("structs: field Level: cannot assign %T", v)
+		}
+	}
+	if v, ok := m["active"]; ok {
+		switch vv := v.(type) {
+		case bool:
+			x.Active = vv
+		default:
+			return fmt.Errorf("structs: field Active: cannot assign %T", v)
+		}
+	}
+	if v, ok := m["tags"]; ok {
+		switch vv := v.(type) {
+		case []string:
+			x.Tags = vv
+		case nil:
+			x.Tags = nil
+		default:
+			return fmt.Errorf("structs: field Tags: cannot assign %T", v)
+		}
+	}
+	if v, ok := m["props"]; ok {
+		switch vv := v.(type) {
+		case map[string]interface{}:
+			x.Props = vv
+		case nil:
+			x.Props = nil
+		default:
+			return fmt.Errorf("structs: field Props: cannot assign %T", v)
+		}
+	}
+	if v, ok := m["address"]; ok {
+		switch vv := v.(type) {
+		case Address:
+			x.Address = vv
+		case map[string]interface{}:
+			if err := x.Address.FromMap(vv); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("structs: field Address: cannot assign %T", v)
+		}
+	}
+	if v, ok := m["home"]; ok {
+		switch vv := v.(type) {
+		case *Address:
+			x.Home = vv
+		case Address:
+			x.Home = &vv
+		case map[string]interface{}:
+			if x.Home == nil {
+				x.Home = new(Address)
+			}
+			if err := x.Home.FromMap(vv); err != nil {
+				return err
+			}
+		case nil:
+			x.Home = nil
+		default:
+			return fmt.Errorf("structs: field Home: cannot assign %T", v)
+		}
+	}
+	if err := x.Work.FromMap(m); err != nil {
+		return err
+	}
+	ptr25 := x.Contact
+	if ptr25 == nil {
+		ptr25 = new(Contact)
+	}
+	if err := ptr25.FromMap(m); err != nil {
+		return err
+	}
+	if x.Contact != nil || !ptr25.IsZero() {
+		x.Contact = ptr25
+	}
+	if v, ok := m["raw"]; ok {
+		switch vv := v.(type) {
+		case Address:
+			x.Raw = vv
+		case map[string]interface{}:
+			if err := x.Raw.FromMap(vv); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("structs: field Raw: cannot assign %T", v)
+		}
+	}
+	if v, ok := m["meta"]; ok {
+		switch vv := v.(type) {
+		case Meta:
+			x.Meta = vv
+		default:
+			return fmt.Errorf("structs: field Meta: cannot assign %T", v)
+		}
+	}
+	if v, ok := m["previous"]; ok {
+		switch vv := v.(type) {
+		case []Address:
+			x.Previous = vv
+		case nil:
+			x.Previous = nil
+		default:
+			return fmt.Errorf("structs: field Previous: cannot assign %T", v)
+		}
+	}
+	if v, ok := m["others"]; ok {
+		switch vv := v.(type) {
+		case []*Address:
+			x.Others = vv
+		case nil:
+			x.Others = nil
+		default:
+			return fmt.Errorf("structs: field Others: cannot assign %T", v)
+		}
+	}
+	if v, ok := m["by_name"]; ok {
+		switch vv := v.(type) {
+		case map[string]Address:
+			x.ByName = vv
+		case nil:
+			x.ByName = nil
+		default:
+			return fmt.Errorf("structs: field ByName: cannot assign %T", v)
+		}
+	}
+	if v, ok := m["by_id"]; ok {
+		switch vv := v.(type) {
+		case map[int]*Address:
+			x.ByID = vv
+		case nil:
+			x.ByID = nil
+		default:
+			return fmt.Errorf("structs: field ByID: cannot assign %T", v)
+		}
+	}
+	if v, ok := m["by_day"]; ok {
+		switch vv := v.(type) {
+		case map[time.Weekday]Address:
+			x.ByDay = vv
+		case nil:
+			x.ByDay = nil
+		default:
+			return fmt.Errorf("structs: field ByDay: cannot assign %T", v)
+		}
+	}
+	if v, ok := m["created_at"]; ok {
+		switch vv := v.(type) {
+		case time.Time:
+			x.CreatedAt = vv
+		default:
+			return fmt.Errorf("structs: field CreatedAt: cannot assign %T", v)
+		}
+	}
+	if v, ok := m["updated_at"]; ok {
+		switch vv := v.(type) {
+		case *time.Time:
+			x.UpdatedAt = vv
+		case nil:
+			x.UpdatedAt = nil
+		default:
+			return fmt.Errorf("structs: field UpdatedAt: cannot assign %T", v)
+		}
+	}
+	if v, ok := m["any"]; ok {
+		x.Any = v
+	}
+	if v, ok := m["password"]; ok {
+		switch vv := v.(type) {
+		case string:
+			x.Password = vv
+		default:
+			return fmt.Errorf("structs: field Password: cannot assign %T", v)
+		}
+	}
+	if v, ok := m["card"]; ok {
+		switch vv := v.(type) {
+		case string:
+			x.Card = vv
+		default:
+			return fmt.Errorf("structs: field Card: cannot assign %T", v)
+		}
+	}
+	if v, ok := m["born"]; ok {
+		switch vv := v.(type) {
+		case time.Time:
+			x.Born = vv
+		case string, int, int64, float64:
+			t, err := structs.ParseTime(vv, "2006-01-02")
+			if err != nil {
+				return fmt.Errorf("structs: field Born: %w", err)
+			}
+			x.Born = t
+		default:
+			return fmt.Errorf("structs: field Born: cannot assign %T", v)
+		}
+	}
+	if v, ok := m["seen"]; ok {
+		switch vv := v.(type) {
+		case *time.Time:
+			x.Seen = vv
+		case string, int, int64, float64:
+			t, err := structs.ParseTime(vv, "Jan 2, 2006")
+			if err != nil {
+				return fmt.Errorf("structs: field Seen: %w", err)
+			}
+			x.Seen = &t
+		case nil:
+			x.Seen = nil
+		default:
+			return fmt.Errorf("structs: field Seen: cannot assign %T", v)
+		}
+	}
+	if v, ok := m["price"]; ok {
+		switch vv := v.(type) {
+		case float64:
+			x.Price = vv
+		default:
+			return fmt.Errorf("structs: field Price: cannot assign %T", v)
+		}
+	}
+	sub26 := make(map[string]interface{})
+	for k, v := range m {
+		if strings.HasPrefix(k, "office_") {
+			sub26[k[7:]] = v
+		}
+	}
+	if err := x.Office.FromMap(sub26); err != nil {
+		return err
+	}
+	if v, ok := m["expires"]; ok {
+		switch vv := v.(type) {
+		case time.Time:
+			x.Expires = vv
+		case string, int, int64, float64:
+			t, err := structs.ParseTime(vv, "unix")
+			if err != nil {
+				return fmt.Errorf("structs: field Expires: %w", err)
+			}
+			x.Expires = t
+		default:
+			return fmt.Errorf("structs: field Expires: cannot assign %T", v)
+		}
+	}
+	if v, ok := m["timeout"]; ok {
+		switch vv := v.(type) {
+		case time.Duration:
+			x.Timeout = vv
+		case string, int, int64, float64:
+			t, err := structs.ParseDuration(vv, "string")
+			if err != nil {
+				return fmt.Errorf("structs: field Timeout: %w", err)
+			}
+			x.Timeout = t
+		default:
+			return fmt.Errorf("structs: field Timeout: cannot assign %T", v)
+		}
+	}
+	if v, ok := m["wait"]; ok {
+		switch vv := v.(type) {
+		case *time.Duration:
+			x.Wait = vv
+		case string, int, int64, float64:
+			t, err := structs.ParseDuration(vv, "nanos")
+			if err != nil {
+				return fmt.Errorf("structs: field Wait: %w", err)
+			}
+			x.Wait = &t
+		case nil:
+			x.Wait = nil
+		default:
+			return fmt.Errorf("structs: field Wait: cannot assign %T", v)
+		}
+	}
+	if v, ok := m["deadline"]; ok {
+		switch vv := v.(type) {
+		case time.Time:
+			x.Deadline = vv
+		default:
+			return fmt.Errorf("structs: field Deadline: cannot assign %T", v)
+		}
+	}
+	if v, ok := m["nickname"]; ok {
+		switch vv := v.(type) {
+		case sql.NullString:
+			x.Nickname = vv
+		default:
+			return fmt.Errorf("structs: field Nickname: cannot assign %T", v)
+		}
+	}
+	if err := x.Extra.FromMap(m); err != nil {
+		return err
+	}
+	return nil
+}
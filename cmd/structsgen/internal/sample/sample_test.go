@@ -0,0 +1,220 @@
+package sample
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/things-go/structs"
+)
+
+func sampleUsers() map[string]User {
+	age := 18
+	count := uint(3)
+	now := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	day := time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)
+	wait := 3 * time.Second
+	return map[string]User{
+		"zero": {},
+		"full": {
+			ID:        1,
+			Name:      "gopher",
+			Email:     "gopher@golang.org",
+			Age:       &age,
+			Score:     0.1,
+			Count:     &count,
+			Level:     "admin",
+			Active:    true,
+			Tags:      []string{"a", "b"},
+			Props:     map[string]interface{}{"k": "v"},
+			Address:   Address{City: "shanghai", Zip: "200000"},
+			Home:      &Address{City: "beijing"},
+			Work:      Address{City: "hangzhou"},
+			Contact:   &Contact{Phone: "123"},
+			Raw:       Address{City: "raw"},
+			Meta:      Meta{Version: 2},
+			Previous:  []Address{{City: "p1"}, {}},
+			Others:    []*Address{{City: "o1"}, nil},
+			ByName:    map[string]Address{"n": {City: "n1"}},
+			ByID:      map[int]*Address{1: {City: "i1"}},
+			ByDay:     map[time.Weekday]Address{time.Monday: {City: "d1"}},
+			CreatedAt: now,
+			UpdatedAt: &now,
+			Any:       &Address{City: "any"},
+			Password:  "password",
+			Card:      "4111111111111111",
+			Born:      time.Date(2000, 1, 2, 0, 0, 0, 0, time.UTC),
+			Seen:      &day,
+			Price:     3.14159,
+			Office:    Address{City: "office", Zip: "100000"},
+			Expires:   now,
+			Timeout:   90 * time.Second,
+			Wait:      &wait,
+			Deadline:  now,
+			Nickname:  sql.NullString{String: "gg", Valid: true},
+			Extra:     Extra{Note: "extra"},
+			Ignored:   "ignored",
+			secret:    "secret",
+		},
+		"any value": {
+			Any: 1,
+		},
+		"secret interfaces": {
+			Props: map[string]interface{}{"secret": Secret{Token: "token"}},
+			Any:   []interface{}{&Secret{Token: "token"}},
+		},
+		"zero nested": {
+			Meta: Meta{},
+			Home: &Address{},
+			Any:  Meta{},
+		},
+	}
+}
+
+func TestGenerated_ToMap(t *testing.T) {
+	for name, u := range sampleUsers() {
+		u := u
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, structs.Map(u), u.ToMap())
+			require.Equal(t, structs.Map(&u), u.ToMap())
+		})
+	}
+}
+
+func TestGenerated_Values(t *testing.T) {
+	for name, u := range sampleUsers() {
+		u := u
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, structs.Values(u), u.Values())
+		})
+	}
+}
+
+func TestGenerated_Redact(t *testing.T) {
+	u := sampleUsers()["full"]
+	m := u.ToMap()
+	require.Equal(t, structs.RedactMask, m["password"])
+	require.Equal(t, structs.RedactMask+"1111", m["card"])
+
+	m = sampleUsers()["secret interfaces"].ToMap()
+	require.Equal(t, map[string]interface{}{"secret": Secret{Token: structs.RedactMask}}, m["props"])
+	require.Equal(t, []interface{}{&Secret{Token: structs.RedactMask}}, m["any"])
+
+	structs.SetRedactMode(structs.RedactNone)
+	defer structs.SetRedactMode(structs.RedactFull)
+	require.Equal(t, structs.Map(u), u.ToMap())
+	require.Equal(t, structs.Values(u), u.Values())
+	require.Equal(t, "password", u.ToMap()["password"])
+}
+
+func TestGenerated_FormatPrefix(t *testing.T) {
+	m := sampleUsers()["full"].ToMap()
+	require.Equal(t, "2000-01-02", m["born"])
+	require.Equal(t, "Jan 2, 2021", m["seen"])
+	require.Equal(t, "3.14", m["price"])
+	require.Equal(t, "office", m["office_city"])
+	require.Equal(t, "100000", m["office_zip"])
+	require.Equal(t, (*time.Time)(nil), User{}.ToMap()["seen"])
+}
+
+func TestGenerated_TimeFormat(t *testing.T) {
+	u := sampleUsers()["full"]
+	m := u.ToMap()
+	require.Equal(t, u.Expires.Unix(), m["expires"])
+	require.Equal(t, "1m30s", m["timeout"])
+	require.Equal(t, int64(3*time.Second), m["wait"])
+
+	var got User
+	require.NoError(t, got.FromMap(map[string]interface{}{
+		"expires": float64(u.Expires.Unix()),
+		"timeout": "1m30s",
+		"wait":    "3000000000",
+		"born":    "2000-01-02",
+	}))
+	require.Equal(t, User{Expires: u.Expires, Timeout: u.Timeout, Wait: u.Wait, Born: u.Born}, got)
+	require.Error(t, got.FromMap(map[string]interface{}{"timeout": "1x"}))
+	require.Error(t, got.FromMap(map[string]interface{}{"expires": "now"}))
+}
+
+func TestGenerated_MapKey(t *testing.T) {
+	m := sampleUsers()["full"].ToMap()
+	require.Equal(t, map[string]interface{}{"1": map[string]interface{}{"city": "i1"}}, m["by_id"])
+	require.Equal(t, map[string]interface{}{"Monday": map[string]interface{}{"city": "d1"}}, m["by_day"])
+}
+
+func TestGenerated_OmitZero(t *testing.T) {
+	m := User{}.ToMap()
+	require.NotContains(t, m, "deadline")
+	require.NotContains(t, m, "nickname")
+
+	m = sampleUsers()["full"].ToMap()
+	require.Contains(t, m, "deadline")
+	require.Contains(t, m, "nickname")
+
+	u := User{Nickname: sql.NullString{String: "x"}}
+	require.Contains(t, u.ToMap(), "nickname")
+	structs.RegisterZeroFunc(reflect.TypeOf(sql.NullString{}), func(v interface{}) bool {
+		return !v.(sql.NullString).Valid
+	})
+	defer structs.RegisterZeroFunc(reflect.TypeOf(sql.NullString{}), nil)
+	require.NotContains(t, u.ToMap(), "nickname")
+	require.Equal(t, structs.Map(u), u.ToMap())
+	require.Equal(t, structs.Values(u), u.Values())
+}
+
+func TestGenerated_Names(t *testing.T) {
+	require.Equal(t, structs.Names(User{}), User{}.Names())
+	require.Equal(t, structs.Names(Address{}), Address{}.Names())
+}
+
+func TestGenerated_IsZero(t *testing.T) {
+	for name, u := range sampleUsers() {
+		u := u
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, structs.IsZero(u), u.IsZero())
+		})
+	}
+	require.True(t, User{}.IsZero())
+	require.True(t, User{Raw: Address{}, Home: &Address{}}.IsZero())
+	require.False(t, User{Raw: Address{City: "c"}}.IsZero())
+}
+
+func TestGenerated_FromMap(t *testing.T) {
+	u := sampleUsers()["full"]
+	m := u.ToMap()
+	m["level"] = u.Level
+	m["address"] = u.Address
+	m["home"] = u.Home
+	m["meta"] = u.Meta
+	m["previous"] = u.Previous
+	m["others"] = u.Others
+	m["by_name"] = u.ByName
+	m["by_id"] = u.ByID
+	m["by_day"] = u.ByDay
+	m["any"] = u.Any
+	m["password"] = u.Password
+	m["card"] = u.Card
+	m["price"] = u.Price
+	m["nickname"] = u.Nickname
+
+	var got User
+	require.NoError(t, got.FromMap(m))
+	u.Ignored, u.secret = "", ""
+	require.Equal(t, u, got)
+
+	got = User{}
+	require.NoError(t, got.FromMap(map[string]interface{}{
+		"address": map[string]interface{}{"city": "c1"},
+		"home":    map[string]interface{}{"city": "c2"},
+		"city":    "c3",
+		"age":     nil,
+	}))
+	require.Equal(t, User{Address: Address{City: "c1"}, Home: &Address{City: "c2"}, Work: Address{City: "c3"}}, got)
+
+	require.Error(t, got.FromMap(map[string]interface{}{"id": "1"}))
+	require.Error(t, got.FromMap(map[string]interface{}{"score": "x"}))
+	require.Error(t, got.FromMap(map[string]interface{}{"address": map[string]interface{}{"city": 1}}))
+}
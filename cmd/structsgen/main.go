@@ -0,0 +1,210 @@
+// Structsgen generates reflection free ToMap, Values, Names, IsZero and
+// FromMap methods for struct types, which produce the same output as the
+// Map, Values, Names and IsZero functions of github.com/things-go/structs.
+//
+// The struct types are selected with the -type flag, or by a
+// "//structs:generate" comment in their doc comment. A typical use is
+//
+//   //go:generate structsgen
+//
+//   //structs:generate
+//   type User struct {
+//       Name string `map:"name"`
+//   }
+//
+// in a file of the package, which writes the methods to <package>_structs.go.
+// Fields of interface type and of struct types which are not generated fall
+// back to the structs package at runtime. The generated methods produce the
+// output of a Struct with the default settings, ie: without groups, time
+// formats or normalization, and with the NilAsNil policy.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/things-go/structs"
+)
+
+const (
+	generateDirective = "//structs:generate"
+	generatedHeader   = "// Code generated by structsgen. DO NOT EDIT."
+)
+
+var (
+	typeNames = flag.String("type", "", "comma-separated list of type names; default the types annotated with "+generateDirective)
+	tagName   = flag.String("tag", structs.DefaultTagName, "struct field tag name")
+	output    = flag.String("output", "", "output file name; default <dir>/<package>_structs.go")
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage of structsgen:\n")
+	fmt.Fprintf(os.Stderr, "\tstructsgen [flags] [directory]\n")
+	fmt.Fprintf(os.Stderr, "Flags:\n")
+	flag.PrintDefaults()
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+
+	dir := "."
+	if args := flag.Args(); len(args) > 0 {
+		dir = args[0]
+	}
+	var names []string
+	if *typeNames != "" {
+		names = strings.Split(*typeNames, ",")
+	}
+
+	pkg, src, err := generate(dir, names, *tagName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	outputName := *output
+	if outputName == "" {
+		outputName = filepath.Join(dir, strings.ToLower(pkg)+"_structs.go")
+	}
+	if err = os.WriteFile(outputName, src, 0o644); err != nil { // nolint: gosec
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// generate loads the package in dir and generates the methods of the named
+// types, or of the annotated types if names is empty. It returns the package
+// name and the generated source.
+func generate(dir string, names []string, tagName string) (string, []byte, error) {
+	pkg, files, err := loadPackage(dir)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(names) == 0 {
+		names = annotatedTypes(files)
+		if len(names) == 0 {
+			return "", nil, fmt.Errorf("structsgen: no type annotated with %s in %s", generateDirective, dir)
+		}
+	}
+	src, err := NewGenerator(pkg, tagName).Generate(names)
+	if err != nil {
+		return "", nil, err
+	}
+	return pkg.Name(), src, nil
+}
+
+// loadPackage parses and type checks the package in dir, skipping test files
+// and the files generated by structsgen.
+func loadPackage(dir string) (*types.Package, []*ast.File, error) {
+	bp, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fset := token.NewFileSet()
+	files := make([]*ast.File, 0, len(bp.GoFiles))
+	for _, name := range bp.GoFiles {
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, nil, err
+		}
+		if isGenerated(f) {
+			continue
+		}
+		files = append(files, f)
+	}
+
+	var checkErr error
+	conf := types.Config{
+		Importer: importer.ForCompiler(fset, "source", nil),
+		// methods of the previous generation may be referred to, but they
+		// are skipped above, so do not fail on their type errors.
+		Error: func(err error) {
+			if checkErr == nil && !isGeneratedMethodError(err) {
+				checkErr = err
+			}
+		},
+	}
+	pkg, err := conf.Check(bp.ImportPath, fset, files, nil)
+	if pkg == nil {
+		return nil, nil, err
+	}
+	if checkErr != nil {
+		return nil, nil, checkErr
+	}
+	return pkg, files, nil
+}
+
+// generatedMethodError matches the type errors of the references to the
+// methods which structsgen generates.
+var generatedMethodError = regexp.MustCompile(`\b(field or method|missing method) (ToMap|Values|Names|IsZero|FromMap)\b`)
+
+// isGeneratedMethodError reports whether err is a type error of a reference
+// to a method generated by structsgen, which is missing while the generated
+// file is skipped.
+func isGeneratedMethodError(err error) bool {
+	te, ok := err.(types.Error)
+	return ok && generatedMethodError.MatchString(te.Msg)
+}
+
+func isGenerated(f *ast.File) bool {
+	for _, c := range f.Comments {
+		if c.Pos() > f.Package {
+			break
+		}
+		for _, l := range c.List {
+			if l.Text == generatedHeader {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// annotatedTypes returns the names of the types with generateDirective in
+// their doc comment.
+func annotatedTypes(files []*ast.File) []string {
+	var names []string
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				ts := spec.(*ast.TypeSpec)
+				doc := ts.Doc
+				if doc == nil && len(gd.Specs) == 1 {
+					doc = gd.Doc
+				}
+				if hasDirective(doc) {
+					names = append(names, ts.Name.Name)
+				}
+			}
+		}
+	}
+	return names
+}
+
+func hasDirective(doc *ast.CommentGroup) bool {
+	if doc == nil {
+		return false
+	}
+	for _, c := range doc.List {
+		if strings.TrimSpace(c.Text) == generateDirective {
+			return true
+		}
+	}
+	return false
+}
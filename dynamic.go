@@ -0,0 +1,104 @@
+package structs
+
+import (
+	"fmt"
+	"go/token"
+	"reflect"
+)
+
+// DynamicStruct builds a struct type at runtime by reflect.StructOf, ie: from
+// a CSV header or a JSON schema, which the rest of this package works on the
+// same as on the declared struct types. Example:
+//
+//   s, err := structs.NewDynamicStruct().
+//       AddField("Name", reflect.TypeOf(""), `map:"name"`).
+//       AddField("Age", reflect.TypeOf(0), `map:"age,omitempty"`).
+//       New()
+//
+//   _ = s.Set("name", "fatih")
+//   m := s.Map() // => {"name": "fatih"}
+type DynamicStruct struct {
+	tagName string
+	fields  []reflect.StructField
+	errs    []error
+}
+
+// NewDynamicStruct returns a new DynamicStruct without fields, whose tags are
+// validated with the DefaultTagName.
+func NewDynamicStruct() *DynamicStruct {
+	return &DynamicStruct{tagName: DefaultTagName}
+}
+
+// SetTagName sets the tag name which the tags of the fields are validated
+// with, and which the *Struct returned by New() uses, default is
+// DefaultTagName.
+func (d *DynamicStruct) SetTagName(tagName string) *DynamicStruct {
+	d.tagName = tagName
+	return d
+}
+
+// AddField adds the field name of type typ with the struct tag tag, ie:
+// `map:"name,omitempty"`, to the struct. name must be an exported Go
+// identifier, which is unique in the struct, and tag must be in the
+// conventional format of struct tags. The errors are reported by Type().
+func (d *DynamicStruct) AddField(name string, typ reflect.Type, tag string) *DynamicStruct {
+	switch {
+	case !token.IsIdentifier(name) || !token.IsExported(name):
+		d.errs = append(d.errs, fmt.Errorf("structs: field name %q is not an exported identifier", name))
+		return d
+	case typ == nil:
+		d.errs = append(d.errs, fmt.Errorf("structs: field %s: nil type", name))
+		return d
+	}
+	for _, f := range d.fields {
+		if f.Name == name {
+			d.errs = append(d.errs, fmt.Errorf("structs: duplicate field %s", name))
+			return d
+		}
+	}
+	if _, err := parseStructTag(tag); err != nil {
+		d.errs = append(d.errs, fmt.Errorf("structs: field %s: %w", name, err))
+		return d
+	}
+	d.fields = append(d.fields, reflect.StructField{
+		Name: name,
+		Type: typ,
+		Tag:  reflect.StructTag(tag),
+	})
+	return d
+}
+
+// Type returns the struct type of the fields, in the order they were added.
+// The tags of tagName are checked by LintWithTag(), so the invalid names and
+// options, and the names used by more than one field are errors. All the
+// errors are returned at once as BuildErrors.
+func (d *DynamicStruct) Type() (t reflect.Type, err error) {
+	errs := append(BuildErrors(nil), d.errs...)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			t, err = nil, fmt.Errorf("structs: struct of fields: %v", r)
+		}
+	}()
+	t = reflect.StructOf(d.fields)
+	errs = append(errs, LintWithTag(t, d.tagName)...)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return t, nil
+}
+
+// New returns a new *Struct, with the tag name of d, of a pointer to a new
+// zero value of the struct type, so its fields are settable, ie: by
+// Field.Set() or Set(). For more info refer to DynamicStruct types Type()
+// method.
+func (d *DynamicStruct) New() (*Struct, error) {
+	t, err := d.Type()
+	if err != nil {
+		return nil, err
+	}
+	return New(reflect.New(t).Interface()).SetTagName(d.tagName), nil
+}
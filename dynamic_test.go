@@ -0,0 +1,70 @@
+package structs
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDynamicStruct(t *testing.T) {
+	d := NewDynamicStruct().
+		AddField("Name", reflect.TypeOf(""), `map:"name"`).
+		AddField("Age", reflect.TypeOf(0), `map:"age,omitempty" json:"age"`).
+		AddField("Since", reflect.TypeOf(time.Time{}), `map:"since,format=unix"`)
+
+	typ, err := d.Type()
+	require.NoError(t, err)
+	require.Equal(t, reflect.Struct, typ.Kind())
+	require.Equal(t, 3, typ.NumField())
+	require.Equal(t, `map:"age,omitempty" json:"age"`, string(typ.Field(1).Tag))
+
+	s, err := d.New()
+	require.NoError(t, err)
+	require.NoError(t, s.Set("name", "fatih"))
+	require.NoError(t, s.MustField("Since").Set(time.Unix(100, 0)))
+	require.Equal(t, map[string]interface{}{
+		"name":  "fatih",
+		"since": int64(100),
+	}, s.Map())
+	require.Equal(t, []string{"Name", "Age", "Since"}, s.Names())
+	require.Len(t, s.Fields(), 3)
+}
+
+func TestDynamicStruct_TagName(t *testing.T) {
+	s, err := NewDynamicStruct().
+		SetTagName("json").
+		AddField("Name", reflect.TypeOf(""), `json:"name"`).
+		New()
+	require.NoError(t, err)
+	require.NoError(t, s.Set("name", "fatih"))
+	require.Equal(t, map[string]interface{}{"name": "fatih"}, s.Map())
+}
+
+func TestDynamicStruct_Errors(t *testing.T) {
+	_, err := NewDynamicStruct().
+		AddField("name", reflect.TypeOf(""), "").
+		AddField("First Name", reflect.TypeOf(""), "").
+		AddField("Age", nil, "").
+		AddField("City", reflect.TypeOf(""), "").
+		AddField("City", reflect.TypeOf(""), "").
+		AddField("Zip", reflect.TypeOf(""), `map:"zip`).
+		AddField("Tags", reflect.TypeOf(""), `map:"tags"json:"tags"`).
+		AddField("Street", reflect.TypeOf(""), `map`).
+		Type()
+	require.EqualError(t, err, `structs: field name "name" is not an exported identifier; `+
+		`structs: field name "First Name" is not an exported identifier; `+
+		`structs: field Age: nil type; `+
+		`structs: duplicate field City; `+
+		`structs: field Zip: unterminated value of struct tag key "map"; `+
+		`structs: field Tags: struct tag pairs must be separated by spaces; `+
+		`structs: field Street: malformed struct tag "map"`)
+
+	_, err = NewDynamicStruct().
+		AddField("Name", reflect.TypeOf(""), `map:"name,unknown"`).
+		AddField("Login", reflect.TypeOf(""), `map:"name"`).
+		New()
+	require.EqualError(t, err, `structs: struct.Name: unknown tag option "unknown"; `+
+		`structs: struct.Login: duplicate tag name "name" with field Name`)
+}
@@ -0,0 +1,263 @@
+package structs
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// EqualOption configures Equal and Diff.
+type EqualOption func(*comparer)
+
+// EqualTag sets the tag name of the struct fields, default is DefaultTagName.
+func EqualTag(tagName string) EqualOption {
+	return func(c *comparer) {
+		c.tagName = tagName
+	}
+}
+
+// EqualIgnore ignores the values at paths, which are dotted paths of tag
+// names, slice indexes and map keys, ie: "updated_at", "items.0.id". A "*"
+// segment matches any name, ie: "items.*.id".
+func EqualIgnore(paths ...string) EqualOption {
+	return func(c *comparer) {
+		for _, path := range paths {
+			c.ignores = append(c.ignores, strings.Split(path, "."))
+		}
+	}
+}
+
+// EqualNilEmpty treats nil and empty slices and maps as equal.
+func EqualNilEmpty() EqualOption {
+	return func(c *comparer) {
+		c.nilEmpty = true
+	}
+}
+
+// EqualFloatTolerance treats floats as equal if their difference is not
+// greater than tolerance.
+func EqualFloatTolerance(tolerance float64) EqualOption {
+	return func(c *comparer) {
+		c.tolerance = tolerance
+	}
+}
+
+// Equal reports whether a and b are deeply equal, like reflect.DeepEqual, but
+// only the exported fields of structs are compared, and fields with the tag of
+// "-" are ignored. Values with an Equal method, whose only argument is of the
+// same type and which returns a bool, ie: time.Time, are compared by the
+// method. The comparison can be loosened by opts.
+func Equal(a, b interface{}, opts ...EqualOption) bool {
+	c := newComparer(opts)
+	c.first = true
+	c.compare(nil, reflect.ValueOf(a), reflect.ValueOf(b))
+	return len(c.diffs) == 0
+}
+
+// Diff returns the dotted paths of the values which are not equal in a and b,
+// the root of which is an empty string. For more info refer to Equal()
+// function.
+func Diff(a, b interface{}, opts ...EqualOption) []string {
+	c := newComparer(opts)
+	c.compare(nil, reflect.ValueOf(a), reflect.ValueOf(b))
+	return c.diffs
+}
+
+type visit struct {
+	typ  reflect.Type
+	a, b uintptr
+}
+
+type comparer struct {
+	tagName   string
+	ignores   [][]string
+	nilEmpty  bool
+	tolerance float64
+
+	// first stops the comparison at the first difference.
+	first   bool
+	diffs   []string
+	visited map[visit]bool
+}
+
+func newComparer(opts []EqualOption) *comparer {
+	c := &comparer{
+		tagName: DefaultTagName,
+		visited: make(map[visit]bool),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func (c *comparer) done() bool {
+	return c.first && len(c.diffs) > 0
+}
+
+func (c *comparer) report(path []string) {
+	c.diffs = append(c.diffs, strings.Join(path, "."))
+}
+
+func (c *comparer) ignored(path []string) bool {
+	for _, ignore := range c.ignores {
+		if len(ignore) != len(path) {
+			continue
+		}
+		matched := true
+		for i, segment := range ignore {
+			if segment != "*" && segment != path[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// child returns path with the name appended, without sharing the array of
+// path.
+func child(path []string, name string) []string {
+	p := make([]string, len(path), len(path)+1)
+	copy(p, path)
+	return append(p, name)
+}
+
+func (c *comparer) compare(path []string, a, b reflect.Value) {
+	if c.done() || c.ignored(path) {
+		return
+	}
+	if !a.IsValid() || !b.IsValid() {
+		if a.IsValid() != b.IsValid() {
+			c.report(path)
+		}
+		return
+	}
+	if a.Type() != b.Type() {
+		c.report(path)
+		return
+	}
+	if eq, ok := callEqual(a, b); ok {
+		if !eq {
+			c.report(path)
+		}
+		return
+	}
+
+	switch a.Kind() { // nolint: exhaustive
+	case reflect.Struct:
+		iteratorStructField(a, c.tagName, func(field reflect.StructField) bool {
+			name, _ := parseTag(field.Tag.Get(c.tagName))
+			if name == "" {
+				name = field.Name
+			}
+			c.compare(child(path, name), a.FieldByIndex(field.Index), b.FieldByIndex(field.Index))
+			return !c.done()
+		})
+	case reflect.Ptr:
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() != b.IsNil() {
+				c.report(path)
+			}
+			return
+		}
+		v := visit{a.Type(), a.Pointer(), b.Pointer()}
+		if c.visited[v] {
+			return
+		}
+		c.visited[v] = true
+		c.compare(path, a.Elem(), b.Elem())
+	case reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() != b.IsNil() {
+				c.report(path)
+			}
+			return
+		}
+		c.compare(path, a.Elem(), b.Elem())
+	case reflect.Slice, reflect.Array:
+		if a.Kind() == reflect.Slice && a.IsNil() != b.IsNil() && !c.nilEmpty {
+			c.report(path)
+			return
+		}
+		if a.Len() != b.Len() {
+			c.report(path)
+			return
+		}
+		for i := 0; i < a.Len() && !c.done(); i++ {
+			c.compare(child(path, strconv.Itoa(i)), a.Index(i), b.Index(i))
+		}
+	case reflect.Map:
+		if a.IsNil() != b.IsNil() && !c.nilEmpty {
+			c.report(path)
+			return
+		}
+		for _, k := range sortedMapKeys(a) {
+			p := child(path, fmt.Sprint(k.Interface()))
+			if bv := b.MapIndex(k); bv.IsValid() {
+				c.compare(p, a.MapIndex(k), bv)
+			} else if !c.ignored(p) {
+				c.report(p)
+			}
+			if c.done() {
+				return
+			}
+		}
+		for _, k := range sortedMapKeys(b) {
+			p := child(path, fmt.Sprint(k.Interface()))
+			if !a.MapIndex(k).IsValid() && !c.ignored(p) {
+				c.report(p)
+			}
+			if c.done() {
+				return
+			}
+		}
+	case reflect.Float32, reflect.Float64:
+		if x, y := a.Float(), b.Float(); x != y && !(math.Abs(x-y) <= c.tolerance) {
+			c.report(path)
+		}
+	case reflect.Func:
+		if !a.IsNil() || !b.IsNil() {
+			c.report(path)
+		}
+	default:
+		if !a.CanInterface() || a.Interface() != b.Interface() {
+			c.report(path)
+		}
+	}
+}
+
+// callEqual compares a and b by the Equal method of a, the boolean reports
+// whether a has the method.
+func callEqual(a, b reflect.Value) (equal, ok bool) {
+	if !a.CanInterface() {
+		return false, false
+	}
+	m := a.MethodByName("Equal")
+	if !m.IsValid() {
+		return false, false
+	}
+	t := m.Type()
+	if t.NumIn() != 1 || t.In(0) != a.Type() || t.NumOut() != 1 || t.Out(0).Kind() != reflect.Bool {
+		return false, false
+	}
+	if a.Kind() == reflect.Ptr && (a.IsNil() || b.IsNil()) {
+		return a.IsNil() && b.IsNil(), true
+	}
+	return m.Call([]reflect.Value{b})[0].Bool(), true
+}
+
+// sortedMapKeys returns the keys of the map v, sorted by their string form.
+func sortedMapKeys(v reflect.Value) []reflect.Value {
+	keys := v.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+	})
+	return keys
+}
@@ -0,0 +1,128 @@
+package structs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type equalItem struct {
+	ID    int     `map:"id"`
+	Price float64 `map:"price"`
+}
+
+type equalOrder struct {
+	ID        int               `map:"id"`
+	Items     []equalItem       `map:"items"`
+	Labels    map[string]string `map:"labels"`
+	CreatedAt time.Time         `map:"created_at"`
+	Note      *string           `map:"note"`
+	Any       interface{}       `map:"any"`
+	Cache     []byte            `map:"-"`
+	Self      *equalOrder       `map:"self"`
+	internal  int
+}
+
+type equalVersion struct {
+	Major, Minor int
+}
+
+// Equal ignores the minor version.
+func (v equalVersion) Equal(o equalVersion) bool {
+	return v.Major == o.Major
+}
+
+func newEqualOrder() *equalOrder {
+	note := "note"
+	return &equalOrder{
+		ID:        1,
+		Items:     []equalItem{{ID: 1, Price: 1.1}, {ID: 2, Price: 2.2}},
+		Labels:    map[string]string{"a": "1"},
+		CreatedAt: time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC),
+		Note:      &note,
+		Any:       1,
+		Cache:     []byte("cache"),
+		internal:  1,
+	}
+}
+
+func TestEqual(t *testing.T) {
+	a, b := newEqualOrder(), newEqualOrder()
+	require.True(t, Equal(a, b))
+	require.True(t, Equal(*a, *b))
+
+	// ignored by tag and unexported fields
+	b.Cache, b.internal = nil, 2
+	require.True(t, Equal(a, b))
+
+	// time.Time is compared by its Equal method
+	b.CreatedAt = a.CreatedAt.In(time.FixedZone("UTC+8", 8*3600))
+	require.True(t, Equal(a, b))
+
+	b.Items[1].ID = 3
+	require.False(t, Equal(a, b))
+	b = newEqualOrder()
+	b.Any = int64(1)
+	require.False(t, Equal(a, b))
+	b = newEqualOrder()
+	b.Note = nil
+	require.False(t, Equal(a, b))
+
+	require.False(t, Equal(a, 1))
+	require.True(t, Equal(nil, nil))
+	require.False(t, Equal(nil, a))
+	require.True(t, Equal(equalVersion{1, 2}, equalVersion{1, 3}))
+	require.False(t, Equal(equalVersion{1, 2}, equalVersion{2, 2}))
+
+	// cycles
+	a.Self, b.Self = a, b
+	b.Note = a.Note
+	require.True(t, Equal(a, b))
+}
+
+func TestEqual_Options(t *testing.T) {
+	a, b := newEqualOrder(), newEqualOrder()
+	b.ID = 2
+	b.Items[0].ID = 10
+	b.Items[1].ID = 20
+	require.False(t, Equal(a, b))
+	require.False(t, Equal(a, b, EqualIgnore("id", "items.0.id")))
+	require.True(t, Equal(a, b, EqualIgnore("id", "items.*.id")))
+
+	b = newEqualOrder()
+	b.Items[0].Price = 1.1000001
+	require.False(t, Equal(a, b))
+	require.True(t, Equal(a, b, EqualFloatTolerance(1e-6)))
+
+	a.Labels, b = nil, newEqualOrder()
+	b.Labels = map[string]string{}
+	require.False(t, Equal(a, b))
+	require.True(t, Equal(a, b, EqualNilEmpty()))
+	a.Items, b.Items = []equalItem{}, nil
+	require.True(t, Equal(a, b, EqualNilEmpty()))
+
+	type A struct {
+		ID   int `json:"-"`
+		Name string
+	}
+	require.False(t, Equal(A{1, "a"}, A{2, "a"}))
+	require.True(t, Equal(A{1, "a"}, A{2, "a"}, EqualTag("json")))
+}
+
+func TestDiff(t *testing.T) {
+	a, b := newEqualOrder(), newEqualOrder()
+	require.Empty(t, Diff(a, b))
+
+	b.ID = 2
+	b.Items[1].Price = 3
+	b.Labels = map[string]string{"b": "1"}
+	b.Any = "1"
+	require.Equal(t, []string{"id", "items.1.price", "labels.a", "labels.b", "any"}, Diff(a, b))
+	require.Equal(t, []string{"items.1.price", "labels.a", "labels.b"}, Diff(a, b, EqualIgnore("id", "any")))
+
+	b = newEqualOrder()
+	b.Items = b.Items[:1]
+	require.Equal(t, []string{"items"}, Diff(a, b))
+	require.Equal(t, []string{""}, Diff(a, 1))
+}
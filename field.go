@@ -31,6 +31,23 @@ func (f *Field) Tag(key string) string {
 	return f.field.Tag.Get(key)
 }
 
+// TagName returns the name of the field in the tag of key, or the field name
+// if there is no name in the tag.
+func (f *Field) TagName(key string) string {
+	name, _ := parseTag(f.field.Tag.Get(key))
+	if name == "" {
+		return f.field.Name
+	}
+	return name
+}
+
+// TagOptions returns the options of the tag of key, ie: "omitempty" and
+// "groups=admin" of `map:"name,omitempty,groups=admin"`.
+func (f *Field) TagOptions(key string) TagOptions {
+	_, opts := parseTag(f.field.Tag.Get(key))
+	return TagOptions(opts)
+}
+
 // IsAnonymous returns true if the given field is an anonymous field (embedded)
 func (f *Field) IsAnonymous() bool {
 	return f.field.Anonymous
@@ -69,7 +86,9 @@ func (f *Field) Kind() reflect.Kind {
 
 // Set sets the field to given value v. It returns an error if the field is not
 // settable (not addressable or not exported) or if the given value's type
-// doesn't match the fields type.
+// doesn't match the fields type. A nil v sets a pointer, interface, map,
+// slice, channel or function field to nil, and v is assignable to an interface
+// field it implements.
 func (f *Field) Set(val interface{}) error {
 	// we can't set unexported fields, so be sure this field is exported
 	if !f.IsExported() {
@@ -81,7 +100,11 @@ func (f *Field) Set(val interface{}) error {
 	}
 
 	given := reflect.ValueOf(val)
-	if f.value.Kind() != given.Kind() {
+	if !given.IsValid() && isNillable(f.value.Kind()) {
+		f.value.Set(reflect.Zero(f.value.Type()))
+		return nil
+	}
+	if f.value.Kind() != given.Kind() && (!given.IsValid() || !given.Type().AssignableTo(f.value.Type())) {
 		return fmt.Errorf("structs: wrong kind. got: %s want: %s", given.Kind(), f.value.Kind())
 	}
 
@@ -147,3 +170,12 @@ func (f *Field) Field(name string) (*Field, bool) {
 		defaultTag: f.defaultTag,
 	}, true
 }
+
+// isNillable reports whether the values of kind k can be nil.
+func isNillable(k reflect.Kind) bool {
+	switch k { // nolint: exhaustive
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice, reflect.Chan, reflect.Func:
+		return true
+	}
+	return false
+}
@@ -89,6 +89,11 @@ func TestField_Set(t *testing.T) {
 
 	ba := s.MustField("E").Value().(*Baz)
 	require.Equal(t, "helloWorld", ba.A)
+
+	// nil sets the nillable fields to nil
+	require.NoError(t, f.Set(nil))
+	require.Nil(t, s.MustField("E").Value())
+	require.Error(t, s.MustField("A").Set(nil))
 }
 
 func TestField_CanInterface(t *testing.T) {
@@ -171,6 +176,32 @@ func TestField_Tag(t *testing.T) {
 	require.Empty(t, s.MustField("A").Tag("json"))
 }
 
+func TestField_TagName(t *testing.T) {
+	s := newStruct()
+
+	require.Equal(t, "y", s.MustField("B").TagName("map"))
+	require.Equal(t, "B", s.MustField("B").TagName("json"))
+	require.Equal(t, "c", s.MustField("C").TagName("json"))
+	require.Equal(t, "A", s.MustField("A").TagName("map"))
+}
+
+func TestField_TagOptions(t *testing.T) {
+	type A struct {
+		Name string `map:"name,omitempty,groups=admin|owner"`
+		Age  int
+	}
+	s := New(&A{})
+
+	opts := s.MustField("Name").TagOptions("map")
+	require.Equal(t, TagOptions{"omitempty", "groups=admin|owner"}, opts)
+	require.True(t, opts.Has("omitempty"))
+	require.False(t, opts.Has("groups"))
+	v, ok := opts.Value("groups")
+	require.True(t, ok)
+	require.Equal(t, "admin|owner", v)
+	require.Empty(t, s.MustField("Age").TagOptions("map"))
+}
+
 func TestField_Value(t *testing.T) {
 	s := newStruct()
 
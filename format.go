@@ -0,0 +1,220 @@
+package structs
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// TimeFormat is the format of time.Time values in the output of Map() and
+// Values(), one of the following, or a layout of Time.Format.
+type TimeFormat string
+
+// Time formats.
+const (
+	TimeDefault   TimeFormat = ""          // the time.Time value as is
+	TimeRFC3339   TimeFormat = "rfc3339"   // string of time.RFC3339
+	TimeUnix      TimeFormat = "unix"      // int64 of the Unix seconds
+	TimeUnixMilli TimeFormat = "unixmilli" // int64 of the Unix milliseconds
+)
+
+// DurationFormat is the format of time.Duration values in the output of Map()
+// and Values().
+type DurationFormat string
+
+// Duration formats.
+const (
+	DurationDefault DurationFormat = ""       // the time.Duration value as is
+	DurationString  DurationFormat = "string" // string of Duration.String, ie: "1m30s"
+	DurationNanos   DurationFormat = "nanos"  // int64 of the nanoseconds
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// FormatValue returns v formatted with the format of the option "format",
+// time.Time values by the TimeFormat, time.Duration values by the
+// DurationFormat if format is one, other values by fmt.Sprintf, ie: "%.2f".
+// Pointers are dereferenced, nil pointers are returned as is.
+func FormatValue(v interface{}, format string) interface{} {
+	return formatValue(reflect.ValueOf(v), format)
+}
+
+func formatValue(v reflect.Value, format string) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+	vv := v
+	for vv.Kind() == reflect.Ptr || vv.Kind() == reflect.Interface {
+		if vv.IsNil() {
+			return v.Interface()
+		}
+		vv = vv.Elem()
+	}
+	switch x := vv.Interface().(type) {
+	case time.Time:
+		return formatTime(x, TimeFormat(format))
+	case time.Duration:
+		if f := DurationFormat(format); f == DurationString || f == DurationNanos {
+			return formatDuration(x, f)
+		}
+	}
+	return fmt.Sprintf(format, vv.Interface())
+}
+
+func formatTime(t time.Time, format TimeFormat) interface{} {
+	switch format {
+	case TimeDefault:
+		return t
+	case TimeRFC3339:
+		return t.Format(time.RFC3339)
+	case TimeUnix:
+		return t.Unix()
+	case TimeUnixMilli:
+		return t.UnixMilli()
+	default:
+		return t.Format(string(format))
+	}
+}
+
+func formatDuration(d time.Duration, format DurationFormat) interface{} {
+	switch format {
+	case DurationString:
+		return d.String()
+	case DurationNanos:
+		return int64(d)
+	default:
+		return d
+	}
+}
+
+// timeValue returns the time.Time or time.Duration value of v, or of the
+// value v points to, formatted with the format of s, and reports whether it
+// is formatted.
+func (s *Struct) timeValue(v reflect.Value) (interface{}, bool) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, false
+		}
+		v = v.Elem()
+	}
+	switch {
+	case v.Type() == timeType && s.timeFormat != TimeDefault:
+		return formatTime(v.Interface().(time.Time), s.timeFormat), true
+	case v.Type() == durationType && s.durationFormat != DurationDefault:
+		return formatDuration(time.Duration(v.Int()), s.durationFormat), true
+	default:
+		return nil, false
+	}
+}
+
+// ParseTime returns v, a time.Time, string or number formatted with the
+// format, parsed to time.Time, the reverse of the formatting of time.Time
+// values. Strings are parsed as time.RFC3339 with TimeDefault, and numbers as
+// the Unix seconds in UTC, except with TimeUnixMilli.
+func ParseTime(v interface{}, format TimeFormat) (time.Time, error) {
+	if t, ok := v.(time.Time); ok {
+		return t, nil
+	}
+	if s, ok := v.(string); ok {
+		switch format {
+		case TimeDefault, TimeRFC3339:
+			return time.Parse(time.RFC3339, s)
+		case TimeUnix, TimeUnixMilli:
+			n, err := strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				return time.Time{}, fmt.Errorf("structs: parse time %q: %w", s, err)
+			}
+			v = n
+		default:
+			return time.Parse(string(format), s)
+		}
+	}
+	n, ok := intValue(reflect.ValueOf(v))
+	if !ok {
+		return time.Time{}, fmt.Errorf("structs: cannot parse %T as time", v)
+	}
+	if format == TimeUnixMilli {
+		return time.UnixMilli(n).UTC(), nil
+	}
+	return time.Unix(n, 0).UTC(), nil
+}
+
+// ParseDuration returns v, a time.Duration, string or number formatted with
+// the format, parsed to time.Duration, the reverse of the formatting of
+// time.Duration values. Strings are parsed by time.ParseDuration, except with
+// DurationNanos, and numbers as the nanoseconds.
+func ParseDuration(v interface{}, format DurationFormat) (time.Duration, error) {
+	if d, ok := v.(time.Duration); ok {
+		return d, nil
+	}
+	if s, ok := v.(string); ok {
+		if format != DurationNanos {
+			return time.ParseDuration(s)
+		}
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("structs: parse duration %q: %w", s, err)
+		}
+		return time.Duration(n), nil
+	}
+	n, ok := intValue(reflect.ValueOf(v))
+	if !ok {
+		return 0, fmt.Errorf("structs: cannot parse %T as duration", v)
+	}
+	return time.Duration(n), nil
+}
+
+// intValue returns the value of the integer or float v as int64.
+func intValue(v reflect.Value) (int64, bool) {
+	switch v.Kind() { // nolint: exhaustive
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return int64(v.Float()), true
+	default:
+		return 0, false
+	}
+}
+
+// parseTimeValue returns src parsed to t, which is time.Time, time.Duration
+// or a pointer to them, with the formats, if src is not assignable to t. It
+// reports whether src is parsed.
+func parseTimeValue(t reflect.Type, src reflect.Value, tf TimeFormat, df DurationFormat) (reflect.Value, bool, error) {
+	if src.Type().AssignableTo(t) {
+		return src, false, nil
+	}
+	et := t
+	if et.Kind() == reflect.Ptr {
+		et = et.Elem()
+	}
+	var v interface{}
+	var err error
+	switch et {
+	case timeType:
+		v, err = ParseTime(src.Interface(), tf)
+	case durationType:
+		v, err = ParseDuration(src.Interface(), df)
+	default:
+		return src, false, nil
+	}
+	if err != nil {
+		return src, true, err
+	}
+	pv := reflect.ValueOf(v)
+	if t.Kind() == reflect.Ptr {
+		p := reflect.New(pv.Type())
+		p.Elem().Set(pv)
+		pv = p
+	}
+	return pv, true, nil
+}
+
+// flattenPrefix returns the prefix of the keys of a flattened struct, the
+// value of the option "prefix".
+func flattenPrefix(tagOpts tagOptions) string {
+	prefix, _ := tagOpts.Value("prefix")
+	return prefix
+}
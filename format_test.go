@@ -0,0 +1,225 @@
+package structs
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type formatAddress struct {
+	City string `map:"city"`
+	Zip  string `map:"zip"`
+}
+
+type formatOrder struct {
+	CreatedAt time.Time     `map:"created_at,format=2006-01-02"`
+	PaidAt    *time.Time    `map:"paid_at,format='Jan 2, 2006'"`
+	Price     float64       `map:"price,format=%.2f"`
+	Count     *int          `map:"count,format=%03d"`
+	Ship      formatAddress `map:",flatten,prefix=ship_"`
+	Bill      formatAddress `map:",flatten"`
+}
+
+func TestFormatValue(t *testing.T) {
+	day := time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)
+	n := 7
+	require.Equal(t, "2021-01-02", FormatValue(day, "2006-01-02"))
+	require.Equal(t, "2021-01-02", FormatValue(&day, "2006-01-02"))
+	require.Equal(t, "3.14", FormatValue(3.14159, "%.2f"))
+	require.Equal(t, "007", FormatValue(&n, "%03d"))
+	require.Equal(t, (*int)(nil), FormatValue((*int)(nil), "%d"))
+	require.Nil(t, FormatValue(nil, "%d"))
+}
+
+func TestStruct_Format(t *testing.T) {
+	day := time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)
+	n := 7
+	o := formatOrder{
+		CreatedAt: day,
+		PaidAt:    &day,
+		Price:     3.14159,
+		Count:     &n,
+		Ship:      formatAddress{City: "a", Zip: "1"},
+		Bill:      formatAddress{City: "b", Zip: "2"},
+	}
+	require.Equal(t, map[string]interface{}{
+		"created_at": "2021-01-02",
+		"paid_at":    "Jan 2, 2021",
+		"price":      "3.14",
+		"count":      "007",
+		"ship_city":  "a",
+		"ship_zip":   "1",
+		"city":       "b",
+		"zip":        "2",
+	}, Map(o))
+	require.Equal(t, []interface{}{"2021-01-02", "Jan 2, 2021", "3.14", "007", "a", "1", "b", "2"}, Values(o))
+	require.Equal(t, (*time.Time)(nil), Map(formatOrder{})["paid_at"])
+}
+
+func TestFlattenPrefix(t *testing.T) {
+	o := &formatOrder{Ship: formatAddress{City: "a"}}
+
+	m, err := MapMasked(o, []string{"ship_city", "city"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"ship_city": "a", "city": ""}, m)
+	require.Error(t, New(o).ValidateMask([]string{"ship_ship_city"}))
+
+	require.Equal(t, []string{"created_at", "paid_at", "price", "count", "ship_city", "ship_zip", "city", "zip"}, New(o).Keys())
+	require.NoError(t, New(o).Set("ship_zip", "2"))
+	require.Equal(t, "2", o.Ship.Zip)
+
+	mg := NewMerger()
+	require.NoError(t, mg.Merge(o, map[string]interface{}{"ship_city": "b", "city": "c"}))
+	require.Equal(t, formatAddress{City: "b", Zip: "2"}, o.Ship)
+	require.Equal(t, formatAddress{City: "c"}, o.Bill)
+	require.Equal(t, map[string]int{"ship_city": 0, "city": 0}, mg.Sources())
+
+	require.Equal(t, []string{"created_at", "paid_at", "price", "count", "ship_city", "ship_zip", "city", "zip"}, Columns(o))
+	require.Empty(t, Lint(o))
+
+	type Dup struct {
+		ShipCity string        `map:"ship_city"`
+		Ship     formatAddress `map:",flatten,prefix=ship_"`
+	}
+	require.Len(t, Lint(Dup{}), 1)
+
+	b, err := Schema(reflect.TypeOf(formatOrder{}))
+	require.NoError(t, err)
+	require.Contains(t, string(b), `"ship_city"`)
+}
+
+type formatJob struct {
+	Name    string         `map:"name"`
+	Start   time.Time      `map:"start"`
+	End     *time.Time     `map:"end"`
+	Timeout time.Duration  `map:"timeout"`
+	Retry   time.Duration  `map:"retry,format=nanos"`
+	Created time.Time      `map:"created,format=2006-01-02"`
+	Label   time.Time      `map:"label,string"`
+	Steps   []formatStep   `map:"steps"`
+	Raw     time.Time      `map:"raw,omitnested"`
+	Nil     *time.Duration `map:"nil"`
+}
+
+type formatStep struct {
+	At time.Time `map:"at"`
+}
+
+func TestStruct_SetTimeFormat(t *testing.T) {
+	start := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	job := formatJob{
+		Name:    "job",
+		Start:   start,
+		End:     &start,
+		Timeout: 90 * time.Second,
+		Retry:   time.Second,
+		Created: start,
+		Label:   start,
+		Steps:   []formatStep{{At: start}},
+		Raw:     start,
+	}
+
+	require.Equal(t, start, Map(job)["start"])
+	require.Equal(t, 90*time.Second, Map(job)["timeout"])
+
+	m := New(job).SetTimeFormat(TimeUnix).SetDurationFormat(DurationString).Map()
+	require.Equal(t, map[string]interface{}{
+		"name":    "job",
+		"start":   start.Unix(),
+		"end":     start.Unix(),
+		"timeout": "1m30s",
+		"retry":   int64(time.Second),
+		"created": "2021-01-02",
+		"label":   "1609556645",
+		"steps":   []interface{}{map[string]interface{}{"at": start.Unix()}},
+		"raw":     start,
+		"nil":     (*time.Duration)(nil),
+	}, m)
+
+	values := New(job).SetTimeFormat(TimeRFC3339).SetDurationFormat(DurationNanos).Values()
+	require.Equal(t, []interface{}{
+		"job", "2021-01-02T03:04:05Z", "2021-01-02T03:04:05Z", int64(90 * time.Second), int64(time.Second),
+		"2021-01-02", "2021-01-02T03:04:05Z", job.Steps, start, (*time.Duration)(nil),
+	}, values)
+
+	m = New(job).SetTimeFormat("Jan 2").Map()
+	require.Equal(t, "Jan 2", m["start"])
+	require.Equal(t, map[string]interface{}{"at": "Jan 2"}, m["steps"].([]interface{})[0])
+	require.Equal(t, start.UnixMilli(), FormatValue(start, string(TimeUnixMilli)))
+	require.Equal(t, "1m30s", FormatValue(90*time.Second, string(DurationString)))
+	require.Equal(t, "90000000000", FormatValue(90*time.Second, "%d"))
+}
+
+func TestParseTime(t *testing.T) {
+	start := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, tt := range []struct {
+		v      interface{}
+		format TimeFormat
+	}{
+		{start, TimeDefault},
+		{"2021-01-02T03:04:05Z", TimeDefault},
+		{"2021-01-02T03:04:05Z", TimeRFC3339},
+		{start.Unix(), TimeDefault},
+		{float64(start.Unix()), TimeUnix},
+		{"1609556645", TimeUnix},
+		{start.UnixMilli(), TimeUnixMilli},
+		{"2021-01-02 03:04:05", "2006-01-02 15:04:05"},
+	} {
+		got, err := ParseTime(tt.v, tt.format)
+		require.NoError(t, err)
+		require.True(t, start.Equal(got), "%v %s", tt.v, tt.format)
+	}
+	_, err := ParseTime("x", TimeUnix)
+	require.Error(t, err)
+	_, err = ParseTime(true, TimeDefault)
+	require.Error(t, err)
+
+	for _, tt := range []struct {
+		v      interface{}
+		format DurationFormat
+	}{
+		{time.Second, DurationDefault},
+		{"1s", DurationString},
+		{"1000000000", DurationNanos},
+		{int64(time.Second), DurationNanos},
+		{float64(time.Second), DurationDefault},
+	} {
+		got, err := ParseDuration(tt.v, tt.format)
+		require.NoError(t, err)
+		require.Equal(t, time.Second, got)
+	}
+	_, err = ParseDuration("1x", DurationString)
+	require.Error(t, err)
+}
+
+func TestMerge_TimeFormat(t *testing.T) {
+	start := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	var job formatJob
+	require.NoError(t, NewMerger().SetTimeFormat(TimeUnixMilli).Merge(&job, map[string]interface{}{
+		"start":   start.UnixMilli(),
+		"end":     "1609556645000",
+		"timeout": "1m30s",
+		"retry":   int64(time.Second),
+		"created": "2021-01-02",
+	}))
+	require.Equal(t, start, job.Start)
+	require.Equal(t, &start, job.End)
+	require.Equal(t, 90*time.Second, job.Timeout)
+	require.Equal(t, time.Second, job.Retry)
+	require.Equal(t, time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC), job.Created)
+
+	require.Error(t, Merge(&job, map[string]interface{}{"start": "now"}))
+
+	require.NoError(t, New(&job).Set("timeout", "2s"))
+	require.Equal(t, 2*time.Second, job.Timeout)
+	require.Error(t, New(&job).Set("end", "x"))
+}
+
+func TestSchema_Format(t *testing.T) {
+	b, err := Schema(formatJob{})
+	require.NoError(t, err)
+	require.Contains(t, string(b), `"retry":{"type":"integer"}`)
+	require.Contains(t, string(b), `"created":{"type":"string"}`)
+}
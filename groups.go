@@ -0,0 +1,53 @@
+package structs
+
+import (
+	"strings"
+)
+
+// SetGroups sets the active groups, which select the fields by the tag option
+// of "groups", a "|" separated list of group names. Example:
+//
+//   // Field only appears for the admin and owner groups.
+//   Email string `map:"email,groups=admin|owner"`
+//
+// A field without the option of "groups" belongs to every group. When no
+// groups are active, which is the default, every field is selected. The
+// groups apply to Map, Values and Names, and recursively to nested structs.
+func (s *Struct) SetGroups(groups ...string) *Struct {
+	s.groups = groups
+	return s
+}
+
+// MapForGroups is the same as Map, but only outputs the fields belonging to
+// any of the groups, the active groups of s are not changed. For more info
+// refer to Struct types SetGroups() method.
+func (s *Struct) MapForGroups(groups ...string) map[string]interface{} {
+	return s.clone().SetGroups(groups...).Map()
+}
+
+// MapForGroups converts the given struct to a map[string]interface{} with the
+// fields belonging to any of the groups. For more info refer to Struct types
+// SetGroups() method. It panics if s's kind is not struct.
+func MapForGroups(s interface{}, groups ...string) map[string]interface{} {
+	return New(s).MapForGroups(groups...)
+}
+
+// inGroups reports whether a field with the tag options belongs to any of the
+// active groups.
+func (s *Struct) inGroups(tagOpts tagOptions) bool {
+	if len(s.groups) == 0 {
+		return true
+	}
+	groups, ok := tagOpts.Value("groups")
+	if !ok {
+		return true
+	}
+	for _, group := range strings.Split(groups, "|") {
+		for _, active := range s.groups {
+			if group == active {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package structs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type groupsProfile struct {
+	Bio   string `map:"bio"`
+	Phone string `map:"phone,groups=owner"`
+}
+
+type groupsUser struct {
+	Name    string          `map:"name"`
+	Email   string          `map:"email,groups=admin|owner"`
+	Role    string          `map:"role,groups=admin"`
+	Profile groupsProfile   `map:"profile"`
+	Friends []groupsProfile `map:"friends,groups=owner"`
+}
+
+func newGroupsUser() *groupsUser {
+	return &groupsUser{
+		Name:    "gopher",
+		Email:   "gopher@golang.org",
+		Role:    "root",
+		Profile: groupsProfile{Bio: "bio", Phone: "110"},
+		Friends: []groupsProfile{{Bio: "friend", Phone: "120"}},
+	}
+}
+
+func TestStruct_MapForGroups(t *testing.T) {
+	u := newGroupsUser()
+
+	require.Equal(t, map[string]interface{}{
+		"name":    "gopher",
+		"profile": map[string]interface{}{"bio": "bio"},
+	}, MapForGroups(u, "public"))
+
+	require.Equal(t, map[string]interface{}{
+		"name":    "gopher",
+		"email":   "gopher@golang.org",
+		"role":    "root",
+		"profile": map[string]interface{}{"bio": "bio"},
+	}, New(u).MapForGroups("admin"))
+
+	require.Equal(t, map[string]interface{}{
+		"name":    "gopher",
+		"email":   "gopher@golang.org",
+		"profile": map[string]interface{}{"bio": "bio", "phone": "110"},
+		"friends": []interface{}{map[string]interface{}{"bio": "friend", "phone": "120"}},
+	}, MapForGroups(u, "owner"))
+
+	require.Len(t, MapForGroups(u, "admin", "owner"), 5)
+	require.Equal(t, Map(u), MapForGroups(u))
+
+	// the groups of the struct are not changed
+	s := New(u)
+	require.Len(t, s.MapForGroups("public"), 2)
+	require.Equal(t, Map(u), s.Map())
+	require.Equal(t, Names(u), s.Names())
+}
+
+func TestStruct_SetGroups(t *testing.T) {
+	u := newGroupsUser()
+
+	require.Equal(t, []interface{}{"gopher", "bio"}, New(u).SetGroups("public").Values())
+	require.Equal(t, []interface{}{"gopher", "gopher@golang.org", "root", "bio"}, New(u).SetGroups("admin").Values())
+	require.Equal(t, []string{"Name", "Profile"}, New(u).SetGroups("public").Names())
+	require.Equal(t, []string{"Name", "Email", "Profile", "Friends"}, New(u).SetGroups("owner").Names())
+	require.Equal(t, Names(u), New(u).SetGroups().Names())
+}
@@ -0,0 +1,184 @@
+package structs
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// tagOptionKind is the form of a tag option.
+type tagOptionKind int
+
+const (
+	optionFlag  tagOptionKind = 1 << iota // ie: omitempty
+	optionValue                           // ie: groups=admin
+)
+
+// tagOptionKinds are the known tag options.
+var tagOptionKinds = map[string]tagOptionKind{
+	"omitempty":  optionFlag,
+	"omitzero":   optionFlag,
+	"omitnested": optionFlag,
+	"string":     optionFlag,
+	"flatten":    optionFlag,
+	"groups":     optionValue,
+	"format":     optionValue,
+	"prefix":     optionValue,
+	"redact":     optionFlag | optionValue,
+	"sensitive":  optionFlag | optionValue,
+}
+
+// tagOptionValidators validate the values of the key=value tag options.
+var tagOptionValidators = map[string]func(value string) error{
+	"format": func(value string) error {
+		if value == "" {
+			return errors.New("empty format")
+		}
+		return nil
+	},
+	"groups": func(value string) error {
+		for _, group := range strings.Split(value, "|") {
+			if group == "" {
+				return errors.New("empty group")
+			}
+		}
+		return nil
+	},
+	"redact": func(value string) error {
+		_, err := ParseRedactMode(value)
+		return err
+	},
+	"sensitive": func(value string) error {
+		_, err := ParseRedactMode(value)
+		return err
+	},
+}
+
+// Lint returns the errors of the struct tags of s, for more info refer to
+// LintWithTag() function.
+func Lint(s interface{}) []error {
+	return LintWithTag(s, DefaultTagName)
+}
+
+// LintWithTag checks the tagName tags of the exported fields of s, which is a
+// struct, pointer to struct, or reflect.Type of them, and of its nested
+// structs, including the struct elements of slices, arrays and maps. It returns an error for every invalid name, see isValidTag, unknown
+// or malformed option, and for every name used by more than one field of the
+// same struct, including the fields of flattened structs, as they collide in
+// the output of Map().
+func LintWithTag(s interface{}, tagName string) []error {
+	t, ok := s.(reflect.Type)
+	if !ok {
+		if s == nil {
+			return []error{errNilValue}
+		}
+		t = reflect.TypeOf(s)
+	}
+	t = derefType(t)
+	if t.Kind() != reflect.Struct {
+		return []error{errors.New("structs: not struct")}
+	}
+	l := &linter{tagName: tagName, visited: make(map[reflect.Type]bool)}
+	l.lint(t)
+	return l.errs
+}
+
+type linter struct {
+	tagName string
+	visited map[reflect.Type]bool
+	errs    []error
+}
+
+func (l *linter) errorf(t reflect.Type, field, format string, args ...interface{}) {
+	name := t.Name()
+	if name == "" {
+		// ie: the types of reflect.StructOf
+		name = "struct"
+	}
+	l.errs = append(l.errs, fmt.Errorf("structs: %s.%s: %s", name, field, fmt.Sprintf(format, args...)))
+}
+
+func (l *linter) lint(t reflect.Type) {
+	if l.visited[t] {
+		return
+	}
+	l.visited[t] = true
+
+	seen := make(map[string]string) // key -> field
+	l.lintFields(t, t, "", "", seen)
+}
+
+// lintFields checks the fields of t, whose keys are in the output of Map() of
+// root, with the fields of flattened structs prefixed by prefix, and their
+// keys by keyPrefix.
+func (l *linter) lintFields(root, t reflect.Type, prefix, keyPrefix string, seen map[string]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		tag := field.Tag.Get(l.tagName)
+		if tag == "-" {
+			continue
+		}
+		name, opts := parseTag(tag)
+		if name != "" && !isValidTag(name) {
+			l.errorf(root, prefix+field.Name, "invalid tag name %q", name)
+		}
+		l.lintOptions(root, prefix+field.Name, opts)
+
+		ft := derefType(field.Type)
+		if opts.Contains("flatten") && !opts.Contains("omitnested") && ft.Kind() == reflect.Struct {
+			l.lintFields(root, ft, prefix+field.Name+".", keyPrefix+flattenPrefix(opts), seen)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		name = keyPrefix + name
+		if other, ok := seen[name]; ok {
+			l.errorf(root, prefix+field.Name, "duplicate tag name %q with field %s", name, other)
+		} else {
+			seen[name] = prefix + field.Name
+		}
+		if !opts.Contains("omitnested") {
+			l.lintElem(ft)
+		}
+	}
+}
+
+// lintElem checks the struct type t, or the struct element types of t if it
+// is a slice, array or map, which are nested in the output of Map() too.
+func (l *linter) lintElem(t reflect.Type) {
+	switch t.Kind() { // nolint: exhaustive
+	case reflect.Struct:
+		l.lint(t)
+	case reflect.Slice, reflect.Array, reflect.Map:
+		if !l.visited[t] {
+			l.visited[t] = true
+			l.lintElem(derefType(t.Elem()))
+		}
+	}
+}
+
+func (l *linter) lintOptions(t reflect.Type, field string, opts tagOptions) {
+	for _, opt := range opts {
+		key, value, hasValue := strings.Cut(opt, "=")
+		kind, ok := tagOptionKinds[key]
+		switch {
+		case !ok:
+			l.errorf(t, field, "unknown tag option %q", opt)
+		case hasValue && kind&optionValue == 0:
+			l.errorf(t, field, "tag option %q takes no value", key)
+		case !hasValue && kind&optionFlag == 0:
+			l.errorf(t, field, "tag option %q requires a value", key)
+		case hasValue:
+			if validate, ok := tagOptionValidators[key]; ok {
+				if err := validate(value); err != nil {
+					l.errorf(t, field, "invalid tag option %q: %v", opt, err)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package structs
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type lintAddress struct {
+	City string `map:"city,omitempty"`
+	Zip  string `map:"zip,unknown"`
+}
+
+type lintMeta struct {
+	Name string `map:"name"`
+}
+
+type lintUser struct {
+	Name     string       `map:"name,omitempty"`
+	Email    string       `map:"a\"b"`
+	Role     string       `map:"role,groups=admin|"`
+	Password string       `map:"password,redact=last2"`
+	Token    string       `map:"token,sensitive"`
+	Admin    bool         `map:"admin,omitempty=true"`
+	Level    string       `map:"level,groups"`
+	Address  lintAddress  `map:"address"`
+	Home     *lintAddress `map:"home"`
+	Meta     lintMeta     `map:",flatten"`
+	Ignored  string       `map:"-"`
+	Raw      lintAddress  `map:"raw,omitnested"`
+	internal string       `map:"!!"` // nolint: govet
+}
+
+func TestLint(t *testing.T) {
+	errs := Lint(&lintUser{})
+	msgs := make([]string, 0, len(errs))
+	for _, err := range errs {
+		msgs = append(msgs, err.Error())
+	}
+	require.Equal(t, []string{
+		`structs: lintUser.Email: invalid tag name "a\"b"`,
+		`structs: lintUser.Role: invalid tag option "groups=admin|": empty group`,
+		`structs: lintUser.Password: invalid tag option "redact=last2": structs: invalid redact mode "last2"`,
+		`structs: lintUser.Admin: tag option "omitempty" takes no value`,
+		`structs: lintUser.Level: tag option "groups" requires a value`,
+		`structs: lintAddress.Zip: unknown tag option "unknown"`,
+		`structs: lintUser.Meta.Name: duplicate tag name "name" with field Name`,
+	}, msgs)
+
+	require.Equal(t, errs, Lint(reflect.TypeOf(lintUser{})))
+	require.Empty(t, Lint(lintMeta{}))
+	require.Len(t, Lint(1), 1)
+	require.Len(t, Lint(nil), 1)
+
+	type A struct {
+		Name string `yaml:"name,omitempty"`
+		Nick string `yaml:"name"`
+	}
+	require.Empty(t, Lint(A{}))
+	require.Len(t, LintWithTag(A{}, "yaml"), 1)
+}
+
+func TestLint_Elem(t *testing.T) {
+	type item struct {
+		ID string `map:"id,bad"`
+	}
+	type value struct {
+		ID string `map:"id,groups"`
+	}
+	type list []list
+	type B struct {
+		Items  []item            `map:"items"`
+		ByName map[string]*value `map:"by_name"`
+		Array  [2]map[int][]item `map:"array"`
+		Raw    []value           `map:"raw,omitnested"`
+		List   list              `map:"list"`
+		Meta   *lintMeta         `map:",flatten"`
+		Name   string            `map:"name"`
+	}
+	msgs := make([]string, 0, 3)
+	for _, err := range Lint(B{}) {
+		msgs = append(msgs, err.Error())
+	}
+	require.Equal(t, []string{
+		`structs: item.ID: unknown tag option "bad"`,
+		`structs: value.ID: tag option "groups" requires a value`,
+		`structs: B.Name: duplicate tag name "name" with field Meta.Name`,
+	}, msgs)
+}
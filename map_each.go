@@ -0,0 +1,170 @@
+package structs
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var errNotIterable = errors.New("structs: require a slice, array or channel")
+
+// MapIterator converts the elements of a slice, an array or a channel to
+// map[string]interface{} one element at a time, so that large or unbounded
+// sources never have to be materialised as a []map[string]interface{}.
+//
+//   it := structs.NewMapIterator(rows)
+//   for it.Next() {
+//       m := it.Map() // nil if the element is a nil pointer
+//   }
+//   if err := it.Err(); err != nil {
+//       // element is not a struct
+//   }
+//
+// Elements may be structs, pointers to structs or interfaces holding either
+// of them, mixed types are allowed. A nil pointer or nil interface element
+// yields a nil map, any other non struct element stops the iteration with an
+// error.
+type MapIterator struct {
+	value   reflect.Value
+	tagName string
+	reuse   bool
+	done    bool
+	index   int
+	buf     map[string]interface{}
+	current map[string]interface{}
+	err     error
+}
+
+// NewMapIterator returns a new *MapIterator over s, which must be a slice, an
+// array, a receivable channel or a pointer to one of them. A nil s, or a nil
+// channel, yields no elements.
+func NewMapIterator(s interface{}) *MapIterator {
+	it := &MapIterator{
+		tagName: DefaultTagName,
+		index:   -1,
+	}
+	if s == nil {
+		it.done = true
+		return it
+	}
+	v := reflect.Indirect(reflect.ValueOf(s))
+	switch v.Kind() { // nolint: exhaustive
+	case reflect.Slice, reflect.Array:
+	case reflect.Chan:
+		if v.Type().ChanDir()&reflect.RecvDir == 0 {
+			it.err = errNotIterable
+		}
+		// receiving from a nil channel blocks forever
+		it.done = v.IsNil()
+	default:
+		it.err = errNotIterable
+	}
+	it.value = v
+	return it
+}
+
+// SetTagName set struct's field tag name, default is DefaultTagName.
+func (it *MapIterator) SetTagName(tagName string) *MapIterator {
+	it.tagName = tagName
+	return it
+}
+
+// SetReuse sets whether the iterator reuses one map for every element. When
+// enabled the map returned by Map is only valid until the next call to Next,
+// callers must copy anything they want to keep.
+func (it *MapIterator) SetReuse(reuse bool) *MapIterator {
+	it.reuse = reuse
+	return it
+}
+
+// Next advances the iterator to the next element, which will then be
+// available through Map. It returns false when the iteration stops, either by
+// reaching the end of the source or an error. After Next returns false, the
+// Err method will return any error that occurred during iteration.
+func (it *MapIterator) Next() bool {
+	if it.done || it.err != nil {
+		return false
+	}
+
+	var elem reflect.Value
+	if it.value.Kind() == reflect.Chan {
+		v, ok := it.value.Recv()
+		if !ok {
+			it.done = true
+			return false
+		}
+		elem = v
+	} else {
+		if it.index+1 >= it.value.Len() {
+			it.done = true
+			return false
+		}
+		elem = it.value.Index(it.index + 1)
+	}
+	it.index++
+
+	for elem.Kind() == reflect.Ptr || elem.Kind() == reflect.Interface {
+		if elem.IsNil() {
+			it.current = nil
+			return true
+		}
+		elem = elem.Elem()
+	}
+	if elem.Kind() != reflect.Struct {
+		it.err = fmt.Errorf("structs: element %d is not a struct, got %s", it.index, elem.Kind())
+		it.current = nil
+		return false
+	}
+
+	var m map[string]interface{}
+	if it.reuse {
+		if it.buf == nil {
+			it.buf = make(map[string]interface{})
+		}
+		for k := range it.buf {
+			delete(it.buf, k)
+		}
+		m = it.buf
+	} else {
+		m = make(map[string]interface{})
+	}
+	New(elem.Interface()).SetTagName(it.tagName).FillMap(m)
+	it.current = m
+	return true
+}
+
+// Index returns the index of the current element, starting at 0.
+func (it *MapIterator) Index() int {
+	return it.index
+}
+
+// Map returns the current element converted to a map[string]interface{}. It
+// returns nil if the current element is a nil pointer.
+func (it *MapIterator) Map() map[string]interface{} {
+	return it.current
+}
+
+// Err returns the error, if any, that was encountered during iteration.
+func (it *MapIterator) Err() error {
+	return it.err
+}
+
+// MapEach converts every element of s to a map[string]interface{} and calls fn
+// for each. For more info refer to MapEachWithTag() function.
+func MapEach(s interface{}, fn func(i int, m map[string]interface{}) error) error {
+	return MapEachWithTag(s, DefaultTagName, fn)
+}
+
+// MapEachWithTag is the same as MapEach() but with tagName. s may be a slice,
+// an array or a channel, see MapIterator for how the elements are handled.
+// The iteration stops at the first error returned by fn, which is returned
+// as is. Use a MapIterator with SetReuse to avoid allocating a map per element.
+func MapEachWithTag(s interface{}, tagName string, fn func(i int, m map[string]interface{}) error) error {
+	it := NewMapIterator(s).SetTagName(tagName)
+	for it.Next() {
+		if err := fn(it.Index(), it.Map()); err != nil {
+			return err
+		}
+	}
+	return it.Err()
+}
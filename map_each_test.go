@@ -0,0 +1,134 @@
+package structs
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMapEach(t *testing.T) {
+	type Foo struct {
+		A string `map:"a"`
+		B bool
+	}
+
+	t.Run("slice", func(t *testing.T) {
+		var got []map[string]interface{}
+		err := MapEach([]Foo{{"a1", false}, {"a2", true}}, func(i int, m map[string]interface{}) error {
+			require.Equal(t, len(got), i)
+			got = append(got, m)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, []map[string]interface{}{
+			{"a": "a1", "B": false},
+			{"a": "a2", "B": true},
+		}, got)
+	})
+	t.Run("with tag", func(t *testing.T) {
+		type Bar struct {
+			A string `db:"a"`
+		}
+		var got []map[string]interface{}
+		err := MapEachWithTag(&[1]Bar{{"a1"}}, "db", func(i int, m map[string]interface{}) error {
+			got = append(got, m)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, []map[string]interface{}{{"a": "a1"}}, got)
+	})
+	t.Run("nil pointer and mixed types", func(t *testing.T) {
+		type Bar struct {
+			C int
+		}
+		var got []map[string]interface{}
+		err := MapEach([]interface{}{(*Foo)(nil), &Foo{A: "a1"}, nil, Bar{1}}, func(i int, m map[string]interface{}) error {
+			got = append(got, m)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, []map[string]interface{}{
+			nil,
+			{"a": "a1", "B": false},
+			nil,
+			{"C": 1},
+		}, got)
+	})
+	t.Run("not a struct element", func(t *testing.T) {
+		count := 0
+		err := MapEach([]interface{}{Foo{}, 1}, func(i int, m map[string]interface{}) error {
+			count++
+			return nil
+		})
+		require.Error(t, err)
+		require.Equal(t, 1, count)
+	})
+	t.Run("callback error", func(t *testing.T) {
+		errStop := errors.New("stop")
+		count := 0
+		err := MapEach([]Foo{{}, {}}, func(i int, m map[string]interface{}) error {
+			count++
+			return errStop
+		})
+		require.Equal(t, errStop, err)
+		require.Equal(t, 1, count)
+	})
+	t.Run("not iterable", func(t *testing.T) {
+		require.Error(t, MapEach("", func(int, map[string]interface{}) error { return nil }))
+		require.Error(t, MapEach(make(chan<- Foo), func(int, map[string]interface{}) error { return nil }))
+		require.NoError(t, MapEach(nil, func(int, map[string]interface{}) error { return nil }))
+	})
+}
+
+func TestMapIterator(t *testing.T) {
+	type Foo struct {
+		A int
+	}
+
+	t.Run("channel", func(t *testing.T) {
+		ch := make(chan *Foo, 3)
+		ch <- &Foo{1}
+		ch <- nil
+		ch <- &Foo{3}
+		close(ch)
+
+		it := NewMapIterator(ch)
+		var got []map[string]interface{}
+		var index []int
+		for it.Next() {
+			got = append(got, it.Map())
+			index = append(index, it.Index())
+		}
+		require.NoError(t, it.Err())
+		require.Equal(t, []int{0, 1, 2}, index)
+		require.Equal(t, []map[string]interface{}{{"A": 1}, nil, {"A": 3}}, got)
+	})
+	t.Run("nil channel", func(t *testing.T) {
+		var ch chan Foo
+		it := NewMapIterator(ch)
+		require.False(t, it.Next())
+		require.NoError(t, it.Err())
+		require.NoError(t, MapEach(&ch, func(int, map[string]interface{}) error { return nil }))
+	})
+	t.Run("reuse", func(t *testing.T) {
+		type Bar struct {
+			A int    `map:",omitempty"`
+			B string `map:",omitempty"`
+		}
+		it := NewMapIterator([]Bar{{A: 1}, {B: "b"}}).SetReuse(true)
+
+		require.True(t, it.Next())
+		first := it.Map()
+		require.Equal(t, map[string]interface{}{"A": 1}, first)
+
+		require.True(t, it.Next())
+		require.Equal(t, map[string]interface{}{"B": "b"}, it.Map())
+		// the same map is reused, stale keys are removed
+		require.Equal(t, map[string]interface{}{"B": "b"}, first)
+
+		require.False(t, it.Next())
+		require.False(t, it.Next())
+		require.NoError(t, it.Err())
+	})
+}
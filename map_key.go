@@ -0,0 +1,114 @@
+package structs
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// KeyEncoder encodes the map key k, which is not supported by MapKey(), ie: a
+// struct, to the string key in the output of Map().
+type KeyEncoder func(k reflect.Value) (string, error)
+
+// SetKeyEncoder sets the encoder of the map keys of the nested maps which are
+// not supported by MapKey(), default is nil, which fails for them, see MapE().
+func (s *Struct) SetKeyEncoder(enc KeyEncoder) *Struct {
+	s.keyEncoder = enc
+	return s
+}
+
+// MapE is the same as Map(), but returns an error for a map key which is not
+// supported, see MapKey() and SetKeyEncoder(), instead of formatting it by
+// fmt.Sprint().
+func (s *Struct) MapE() (map[string]interface{}, error) {
+	var err error
+	ss := s.clone()
+	ss.keyErr = &err
+	m := ss.Map()
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// MapKey returns the string of the map key k, which is used in the output of
+// Map() for the nested maps. Keys of string kind are used as is, keys which
+// implement encoding.TextMarshaler or fmt.Stringer by their methods, integers,
+// floats and bools by strconv. Pointers and interfaces are dereferenced,
+// other keys return an error.
+func MapKey(k interface{}) (string, error) {
+	return mapKeyString(reflect.ValueOf(k), nil)
+}
+
+func mapKeyString(k reflect.Value, enc KeyEncoder) (string, error) {
+	for k.Kind() == reflect.Interface || (k.Kind() == reflect.Ptr && !isKeyMethods(k)) {
+		if k.IsNil() {
+			return "", fmt.Errorf("structs: nil map key of type %s", k.Type())
+		}
+		k = k.Elem()
+	}
+	if k.Kind() == reflect.String {
+		return k.String(), nil
+	}
+	if k.IsValid() && k.CanInterface() {
+		switch x := k.Interface().(type) {
+		case encoding.TextMarshaler:
+			text, err := x.MarshalText()
+			if err != nil {
+				return "", fmt.Errorf("structs: marshal map key: %w", err)
+			}
+			return string(text), nil
+		case fmt.Stringer:
+			return x.String(), nil
+		}
+	}
+	switch k.Kind() { // nolint: exhaustive
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(k.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(k.Uint(), 10), nil
+	case reflect.Float32:
+		return strconv.FormatFloat(k.Float(), 'f', -1, 32), nil
+	case reflect.Float64:
+		return strconv.FormatFloat(k.Float(), 'f', -1, 64), nil
+	case reflect.Bool:
+		return strconv.FormatBool(k.Bool()), nil
+	case reflect.Invalid:
+		return "", fmt.Errorf("structs: invalid map key")
+	}
+	if enc != nil {
+		return enc(k)
+	}
+	return "", fmt.Errorf("structs: unsupported map key type %s", k.Type())
+}
+
+// isKeyMethods reports whether the non-nil pointer k implements
+// encoding.TextMarshaler or fmt.Stringer.
+func isKeyMethods(k reflect.Value) bool {
+	if k.IsNil() || !k.CanInterface() {
+		return false
+	}
+	switch k.Interface().(type) {
+	case encoding.TextMarshaler, fmt.Stringer:
+		return true
+	}
+	return false
+}
+
+// keyString returns the string of the map key k with the key encoder of s,
+// or fmt.Sprint() of k if it is not supported, whose error is recorded for
+// MapE().
+func (s *Struct) keyString(k reflect.Value) string {
+	key, err := mapKeyString(k, s.keyEncoder)
+	if err == nil {
+		return key
+	}
+	if s.keyErr != nil && *s.keyErr == nil {
+		*s.keyErr = err
+	}
+	if !k.IsValid() || !k.CanInterface() {
+		return fmt.Sprint(k)
+	}
+	return fmt.Sprint(k.Interface())
+}
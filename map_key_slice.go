@@ -1,41 +1,137 @@
 package structs
 
 import (
+	"encoding"
+	"errors"
+	"fmt"
+	"math"
 	"reflect"
+	"sort"
+	"strings"
 )
 
+// Ordered is a constraint that permits any ordered type, the same as
+// golang.org/x/exp/constraints.Ordered.
+type Ordered interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64 |
+		~string
+}
+
+// Entry is a key/value pair of a map.
+type Entry[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+// Keys returns the keys of the map m, in indeterminate order.
+func Keys[K comparable, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// SortedKeys returns the keys of the map m in ascending order.
+func SortedKeys[K Ordered, V any](m map[K]V) []K {
+	keys := Keys(m)
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
+
+// ValuesOfMap returns the values of the map m, in indeterminate order.
+func ValuesOfMap[K comparable, V any](m map[K]V) []V {
+	values := make([]V, 0, len(m))
+	for _, v := range m {
+		values = append(values, v)
+	}
+	return values
+}
+
+// Entries returns the key/value pairs of the map m, in indeterminate order.
+func Entries[K comparable, V any](m map[K]V) []Entry[K, V] {
+	entries := make([]Entry[K, V], 0, len(m))
+	for k, v := range m {
+		entries = append(entries, Entry[K, V]{Key: k, Value: v})
+	}
+	return entries
+}
+
+// KeysFunc returns the keys of the map m for which fn returns true, in
+// indeterminate order.
+func KeysFunc[K comparable, V any](m map[K]V, fn func(k K, v V) bool) []K {
+	keys := make([]K, 0, len(m))
+	for k, v := range m {
+		if fn(k, v) {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
 // KeysOfMap return map key slice, need map key is string,
-// if is not string, or not a map, it will panic.
+// if is not string, or not a map, it will panic. For more info refer to
+// KeysOfMapE() function.
 func KeysOfMap(m interface{}) []string {
-	rv := reflect.Indirect(reflect.ValueOf(m))
-	if rv.Kind() == reflect.Invalid {
-		return []string{}
+	ss, err := KeysOfMapE(m)
+	if err != nil {
+		panic("KeysOfMap: " + strings.TrimPrefix(err.Error(), "structs: "))
 	}
-	if rv.Kind() != reflect.Map {
-		panic("KeysOfMap: require a map")
+	return ss
+}
+
+// KeysOfMapE returns the keys of the map m, or a pointer to it, as strings.
+// The keys must be of string kind, including named string types, or
+// implement encoding.TextMarshaler, otherwise an error is returned. A nil m
+// returns an empty slice.
+func KeysOfMapE(m interface{}) ([]string, error) {
+	rv, err := mapValue(m)
+	if err != nil || !rv.IsValid() {
+		return []string{}, err
 	}
 
 	keys := rv.MapKeys()
 	ss := make([]string, 0, len(keys))
 	for _, key := range keys {
-		key = reflect.Indirect(key)
-		if key.Kind() != reflect.String {
-			panic("KeysOfMap: require string type of map key")
+		if k := reflect.Indirect(key); k.Kind() == reflect.String {
+			ss = append(ss, k.String())
+			continue
+		}
+		tm, ok := key.Interface().(encoding.TextMarshaler)
+		if !ok {
+			return nil, errors.New("structs: require string type of map key")
+		}
+		text, err := tm.MarshalText()
+		if err != nil {
+			return nil, fmt.Errorf("structs: marshal map key: %w", err)
 		}
-		ss = append(ss, key.String())
+		ss = append(ss, string(text))
 	}
-	return ss
+	return ss, nil
 }
 
 // KeysIntOfMap return map key slice, need map key is numeric.
 // (int,int8,int16,int32,int64,uint,uint8,uint16,uint32,uint64).
+// if is not numeric, or not a map, it will panic. For more info refer to
+// KeysIntOfMapE() function.
 func KeysIntOfMap(m interface{}) []int64 {
-	rv := reflect.Indirect(reflect.ValueOf(m))
-	if rv.Kind() == reflect.Invalid {
-		return []int64{}
+	ss, err := KeysIntOfMapE(m)
+	if err != nil {
+		panic("KeysIntOfMap: " + strings.TrimPrefix(err.Error(), "structs: "))
 	}
-	if rv.Kind() != reflect.Map {
-		panic("KeysIntOfMap: require a map")
+	return ss
+}
+
+// KeysIntOfMapE returns the keys of the map m, or a pointer to it, as
+// int64. The keys must be of any integer kind, including named types,
+// otherwise, or if an unsigned key overflows int64, an error is returned. A
+// nil m returns an empty slice.
+func KeysIntOfMapE(m interface{}) ([]int64, error) {
+	rv, err := mapValue(m)
+	if err != nil || !rv.IsValid() {
+		return []int64{}, err
 	}
 
 	keys := rv.MapKeys()
@@ -43,12 +139,29 @@ func KeysIntOfMap(m interface{}) []int64 {
 	for _, key := range keys {
 		key = reflect.Indirect(key)
 		switch key.Kind() { // nolint: exhaustive
-		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
-			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			ss = append(ss, key.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			if key.Uint() > math.MaxInt64 {
+				return nil, fmt.Errorf("structs: map key %d overflows int64", key.Uint())
+			}
+			ss = append(ss, int64(key.Uint()))
 		default:
-			panic("KeysIntOfMap: require integer type of map key")
+			return nil, errors.New("structs: require integer type of map key")
 		}
-		ss = append(ss, key.Int())
 	}
-	return ss
+	return ss, nil
+}
+
+// mapValue returns the map value of m, or a pointer to it, which is invalid
+// if m is nil.
+func mapValue(m interface{}) (reflect.Value, error) {
+	rv := reflect.Indirect(reflect.ValueOf(m))
+	if rv.Kind() == reflect.Invalid {
+		return rv, nil
+	}
+	if rv.Kind() != reflect.Map {
+		return rv, errors.New("structs: require a map")
+	}
+	return rv, nil
 }
@@ -1,6 +1,8 @@
 package structs
 
 import (
+	"fmt"
+	"math"
 	"reflect"
 	"sort"
 	"testing"
@@ -9,8 +11,8 @@ import (
 )
 
 func TestKeysOfMap(t *testing.T) {
-	require.Panics(t, func() { KeysOfMap("no map") })
-	require.Panics(t, func() { KeysOfMap(map[int]struct{}{1: {}}) })
+	require.PanicsWithValue(t, "KeysOfMap: require a map", func() { KeysOfMap("no map") })
+	require.PanicsWithValue(t, "KeysOfMap: require string type of map key", func() { KeysOfMap(map[int]struct{}{1: {}}) })
 	require.Equal(t, []string{}, KeysOfMap(nil))
 	require.Equal(t, []string{}, KeysOfMap(map[int]struct{}{}))
 
@@ -80,8 +82,8 @@ func (p Int64Slices) Less(i, j int) bool { return p[i] < p[j] }
 func (p Int64Slices) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
 
 func TestKeysIntOfMap(t *testing.T) {
-	require.Panics(t, func() { KeysIntOfMap("no map") })
-	require.Panics(t, func() { KeysIntOfMap(map[string]struct{}{"1": {}}) })
+	require.PanicsWithValue(t, "KeysIntOfMap: require a map", func() { KeysIntOfMap("no map") })
+	require.PanicsWithValue(t, "KeysIntOfMap: require integer type of map key", func() { KeysIntOfMap(map[string]struct{}{"1": {}}) })
 	require.Equal(t, []int64{}, KeysIntOfMap(nil))
 	require.Equal(t, []int64{}, KeysIntOfMap(map[string]struct{}{}))
 
@@ -121,3 +123,74 @@ func TestKeysIntOfMap(t *testing.T) {
 		})
 	}
 }
+
+type mapKeyName string
+
+type mapKeyText struct{ a, b int }
+
+func (k mapKeyText) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("%d-%d", k.a, k.b)), nil
+}
+
+type mapKeyUint uint16
+
+func TestKeysOfMapE(t *testing.T) {
+	got, err := KeysOfMapE(map[mapKeyName]int{"a": 1, "b": 2})
+	require.NoError(t, err)
+	sort.Strings(got)
+	require.Equal(t, []string{"a", "b"}, got)
+
+	got, err = KeysOfMapE(map[mapKeyText]int{{1, 2}: 1})
+	require.NoError(t, err)
+	require.Equal(t, []string{"1-2"}, got)
+
+	_, err = KeysOfMapE("no map")
+	require.EqualError(t, err, "structs: require a map")
+	_, err = KeysOfMapE(map[int]int{1: 1})
+	require.EqualError(t, err, "structs: require string type of map key")
+}
+
+func TestKeysIntOfMapE(t *testing.T) {
+	for _, m := range []interface{}{
+		map[uint]bool{1: true, 2: true},
+		map[mapKeyUint]bool{1: true, 2: true},
+		map[int8]bool{1: true, 2: true},
+		&map[uint64]bool{1: true, 2: true},
+	} {
+		got, err := KeysIntOfMapE(m)
+		require.NoError(t, err)
+		sort.Sort(Int64Slices(got))
+		require.Equal(t, []int64{1, 2}, got)
+	}
+	require.Equal(t, []int64{1}, KeysIntOfMap(map[uint]bool{1: true}))
+
+	_, err := KeysIntOfMapE(map[uint64]bool{math.MaxUint64: true})
+	require.EqualError(t, err, "structs: map key 18446744073709551615 overflows int64")
+	_, err = KeysIntOfMapE(map[string]bool{"1": true})
+	require.EqualError(t, err, "structs: require integer type of map key")
+	_, err = KeysIntOfMapE(1)
+	require.EqualError(t, err, "structs: require a map")
+}
+
+func TestKeys(t *testing.T) {
+	m := map[string]int{"c": 3, "a": 1, "b": 2}
+
+	keys := Keys(m)
+	sort.Strings(keys)
+	require.Equal(t, []string{"a", "b", "c"}, keys)
+	require.Equal(t, []string{"a", "b", "c"}, SortedKeys(m))
+	require.Equal(t, []mapKeyUint{1, 2}, SortedKeys(map[mapKeyUint]bool{2: true, 1: false}))
+	require.Empty(t, SortedKeys(map[int]int(nil)))
+
+	values := ValuesOfMap(m)
+	sort.Ints(values)
+	require.Equal(t, []int{1, 2, 3}, values)
+
+	entries := Entries(m)
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+	require.Equal(t, []Entry[string, int]{{"a", 1}, {"b", 2}, {"c", 3}}, entries)
+
+	keys = KeysFunc(m, func(k string, v int) bool { return v > 1 })
+	sort.Strings(keys)
+	require.Equal(t, []string{"b", "c"}, keys)
+}
@@ -0,0 +1,94 @@
+package structs
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type mapKeyItem struct {
+	Name string `map:"name"`
+}
+
+type mapKeyPoint struct {
+	X, Y int
+}
+
+type mapKeyFail struct{}
+
+func (mapKeyFail) MarshalText() ([]byte, error) {
+	return nil, errors.New("fail")
+}
+
+func TestMapKey(t *testing.T) {
+	ip := net.ParseIP("127.0.0.1")
+	for _, tt := range []struct {
+		k    interface{}
+		want string
+	}{
+		{"a", "a"},
+		{mapKeyName("b"), "b"},
+		{-1, "-1"},
+		{uint8(2), "2"},
+		{1.5, "1.5"},
+		{float32(0.1), "0.1"},
+		{true, "true"},
+		{time.Monday, "Monday"},
+		{ip, "127.0.0.1"},
+		{mapKeyText{1, 2}, "1-2"},
+		{&ip, "127.0.0.1"},
+	} {
+		got, err := MapKey(tt.k)
+		require.NoError(t, err)
+		require.Equal(t, tt.want, got)
+	}
+
+	for _, k := range []interface{}{mapKeyPoint{}, [2]int{}, (*int)(nil), mapKeyFail{}, nil} {
+		_, err := MapKey(k)
+		require.Error(t, err, "%v", k)
+	}
+}
+
+func TestStruct_MapKey(t *testing.T) {
+	type T struct {
+		ByID    map[int]mapKeyItem            `map:"by_id"`
+		ByRate  map[float64]*mapKeyItem       `map:"by_rate"`
+		ByDay   map[time.Weekday][]mapKeyItem `map:"by_day"`
+		ByPoint map[mapKeyPoint]mapKeyItem    `map:"by_point"`
+	}
+	v := T{
+		ByID:   map[int]mapKeyItem{1: {"a"}},
+		ByRate: map[float64]*mapKeyItem{0.5: {"b"}},
+		ByDay:  map[time.Weekday][]mapKeyItem{time.Sunday: {{"c"}}},
+	}
+	require.Equal(t, map[string]interface{}{
+		"by_id":    map[string]interface{}{"1": map[string]interface{}{"name": "a"}},
+		"by_rate":  map[string]interface{}{"0.5": map[string]interface{}{"name": "b"}},
+		"by_day":   map[string]interface{}{"Sunday": []interface{}{map[string]interface{}{"name": "c"}}},
+		"by_point": map[string]interface{}{},
+	}, Map(v))
+
+	v.ByPoint = map[mapKeyPoint]mapKeyItem{{1, 2}: {"d"}}
+	require.Equal(t, map[string]interface{}{"{1 2}": map[string]interface{}{"name": "d"}}, Map(v)["by_point"])
+	m, err := New(v).MapE()
+	require.EqualError(t, err, "structs: unsupported map key type structs.mapKeyPoint")
+	require.Nil(t, m)
+
+	// MapE of a nested struct records the error as well
+	_, err = New(struct {
+		T T `map:"t"`
+	}{v}).MapE()
+	require.Error(t, err)
+
+	m, err = New(v).SetKeyEncoder(func(k reflect.Value) (string, error) {
+		p := k.Interface().(mapKeyPoint)
+		return fmt.Sprintf("%d,%d", p.X, p.Y), nil
+	}).MapE()
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"1,2": map[string]interface{}{"name": "d"}}, m["by_point"])
+}
@@ -0,0 +1,266 @@
+package structs
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// maskTree is the parsed form of field mask paths, keyed by tag name. A nil
+// child selects the whole field.
+type maskTree map[string]maskTree
+
+// parseMask parses the dotted paths of a field mask, ie: ["name", "address.city"].
+// A path selecting a whole field overrides the paths selecting its sub fields.
+func parseMask(paths []string) (maskTree, error) {
+	tree := make(maskTree)
+	for _, path := range paths {
+		if path == "" {
+			return nil, errors.New("structs: empty field mask path")
+		}
+		node := tree
+		segments := strings.Split(path, ".")
+		for i, segment := range segments {
+			if segment == "" {
+				return nil, fmt.Errorf("structs: invalid field mask path %q", path)
+			}
+			child, ok := node[segment]
+			if i == len(segments)-1 {
+				node[segment] = nil
+				break
+			}
+			if ok && child == nil {
+				// the whole field is already selected
+				break
+			}
+			if child == nil {
+				child = make(maskTree)
+				node[segment] = child
+			}
+			node = child
+		}
+	}
+	return tree, nil
+}
+
+func (m maskTree) names() []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// lookupTagField returns the exported field of struct type t, whose name in
+// the tagName tag, or field name if there is no name in the tag, is name.
+// The fields of flattened struct fields, or pointers to struct, are searched
+// too, by their names with the prefix of the option "prefix", the same as Map()
+// flattens them, in which case the returned index has more than one element.
+func lookupTagField(t reflect.Type, tagName, name string) (reflect.StructField, []int, bool) {
+	for _, f := range getFieldTypes(t, tagName) {
+		if !f.IsExported() {
+			continue
+		}
+		_, tagOpts := parseTag(f.Tag(tagName))
+		if tagOpts.Contains("flatten") && !tagOpts.Contains("omitnested") {
+			ft := f.Type()
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				prefix := flattenPrefix(tagOpts)
+				if !strings.HasPrefix(name, prefix) {
+					continue
+				}
+				if field, index, ok := lookupTagField(ft, tagName, name[len(prefix):]); ok {
+					return field, append([]int{f.field.Index[0]}, index...), true
+				}
+				continue
+			}
+		}
+		if f.TagName() == name {
+			return f.field, f.field.Index, true
+		}
+	}
+	return reflect.StructField{}, nil, false
+}
+
+// validateMask checks that every path of tree exists in struct type t.
+func validateMask(t reflect.Type, tagName string, tree maskTree, prefix string) error {
+	for _, name := range tree.names() {
+		path := prefix + name
+		field, _, ok := lookupTagField(t, tagName, name)
+		if !ok {
+			return fmt.Errorf("structs: field mask path %q not found", path)
+		}
+		child := tree[name]
+		if child == nil {
+			continue
+		}
+		_, tagOpts := parseTag(field.Tag.Get(tagName))
+		ft := derefType(field.Type)
+		if ft.Kind() != reflect.Struct || tagOpts.Contains("omitnested") || tagOpts.Contains("string") {
+			return fmt.Errorf("structs: field mask path %q is not a nested struct", path)
+		}
+		if err := validateMask(ft, tagName, child, path+"."); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateMask returns an error if any of the dotted paths of tag names does
+// not exist in the struct. A struct tag with the content of "-" can never be
+// selected, and the fields of a flattened struct are selected by their own
+// names, the same as they appear in Map().
+func (s *Struct) ValidateMask(paths []string) error {
+	tree, err := parseMask(paths)
+	if err != nil {
+		return err
+	}
+	return validateMask(s.value.Type(), s.tagName, tree, "")
+}
+
+// MapMasked is the same as Map, but only outputs the fields selected by
+// paths, which are dotted paths of tag names, ie: ["name", "address.city"].
+// It returns an error if any of the paths does not exist, see ValidateMask.
+// Selected fields which are omitted by Map, ie: with the option of
+// "omitempty", are omitted as well.
+func (s *Struct) MapMasked(paths []string) (map[string]interface{}, error) {
+	tree, err := parseMask(paths)
+	if err != nil {
+		return nil, err
+	}
+	if err = validateMask(s.value.Type(), s.tagName, tree, ""); err != nil {
+		return nil, err
+	}
+	return filterMask(s.Map(), tree), nil
+}
+
+func filterMask(m map[string]interface{}, tree maskTree) map[string]interface{} {
+	out := make(map[string]interface{}, len(tree))
+	for name, child := range tree {
+		v, ok := m[name]
+		if !ok {
+			continue
+		}
+		if nested, isMap := v.(map[string]interface{}); isMap && child != nil {
+			v = filterMask(nested, child)
+		}
+		out[name] = v
+	}
+	return out
+}
+
+// MapMasked converts the fields selected by paths of the given struct to a
+// map[string]interface{}. For more info refer to Struct types MapMasked()
+// method. It panics if s's kind is not struct.
+func MapMasked(s interface{}, paths []string) (map[string]interface{}, error) {
+	return MapMaskedWithTag(s, paths, DefaultTagName)
+}
+
+// MapMaskedWithTag is the same as MapMasked() but with tagName.
+func MapMaskedWithTag(s interface{}, paths []string, tagName string) (map[string]interface{}, error) {
+	return New(s).SetTagName(tagName).MapMasked(paths)
+}
+
+// ApplyMask copies the fields selected by paths from src to dst, for more info
+// refer to ApplyMaskWithTag() function.
+func ApplyMask(dst, src interface{}, paths []string) error {
+	return ApplyMaskWithTag(dst, src, paths, DefaultTagName)
+}
+
+// ApplyMaskWithTag copies the fields selected by paths, which are dotted paths
+// of tag names with tagName, from src to dst. dst must be a pointer to struct,
+// and src a struct or pointer to struct of the same type. Nil pointers to
+// nested structs in dst are allocated as needed, and nil pointers in src are
+// handled as zero structs. It returns an error if any of the paths does not
+// exist, see Struct types ValidateMask() method, in which case dst is left
+// untouched.
+func ApplyMaskWithTag(dst, src interface{}, paths []string, tagName string) error {
+	dv := reflect.ValueOf(dst)
+	if dv.Kind() != reflect.Ptr || dv.IsNil() || dv.Elem().Kind() != reflect.Struct {
+		return errors.New("structs: dst must be a non-nil pointer to struct")
+	}
+	dv = dv.Elem()
+	sv, err := structVal(src)
+	if err != nil {
+		return err
+	}
+	if dv.Type() != sv.Type() {
+		return fmt.Errorf("structs: mismatched types, dst: %s src: %s", dv.Type(), sv.Type())
+	}
+
+	tree, err := parseMask(paths)
+	if err != nil {
+		return err
+	}
+	if err = validateMask(dv.Type(), tagName, tree, ""); err != nil {
+		return err
+	}
+	applyMask(dv, sv, tagName, tree)
+	return nil
+}
+
+func applyMask(dst, src reflect.Value, tagName string, tree maskTree) {
+	for name, child := range tree {
+		_, index, _ := lookupTagField(dst.Type(), tagName, name)
+		df, _ := fieldByIndex(dst, index)
+		sf := fieldByIndexZero(src, index)
+		if child == nil {
+			df.Set(sf)
+			continue
+		}
+		for df.Kind() == reflect.Ptr {
+			if df.IsNil() {
+				df.Set(reflect.New(df.Type().Elem()))
+			}
+			df = df.Elem()
+		}
+		for sf.Kind() == reflect.Ptr {
+			if sf.IsNil() {
+				sf = reflect.Zero(derefType(sf.Type()))
+				break
+			}
+			sf = sf.Elem()
+		}
+		applyMask(df, sf, tagName, child)
+	}
+}
+
+// fieldByIndex returns the nested field of the struct v by index, allocating
+// nil pointers to embedded and flattened structs.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, error) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}, errNotSettable
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, nil
+}
+
+// fieldByIndexZero is the same as fieldByIndex, but handles nil pointers to
+// embedded and flattened structs as zero structs.
+func fieldByIndexZero(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v = reflect.Zero(v.Type().Elem())
+			} else {
+				v = v.Elem()
+			}
+		}
+		v = v.Field(x)
+	}
+	return v
+}
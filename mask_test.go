@@ -0,0 +1,145 @@
+package structs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type maskAddress struct {
+	City   string `map:"city"`
+	Street string `map:"street"`
+}
+
+type maskExtra struct {
+	Note string `map:"note"`
+}
+
+type maskMore struct {
+	Tag string `map:"tag"`
+}
+
+type maskUser struct {
+	Name     string       `map:"name"`
+	Email    string       `map:"email,omitempty"`
+	Password string       `map:"-"`
+	Address  maskAddress  `map:"address"`
+	Home     *maskAddress `map:"home"`
+	Extra    maskExtra    `map:",flatten"`
+	More     *maskMore    `map:",flatten"`
+	Raw      maskAddress  `map:"raw,omitnested"`
+}
+
+func TestParseMask(t *testing.T) {
+	tree, err := parseMask([]string{"a.b", "a", "c.d.e", "c.f", "g", "g.h"})
+	require.NoError(t, err)
+	require.Equal(t, maskTree{
+		"a": nil,
+		"c": {"d": {"e": nil}, "f": nil},
+		"g": nil,
+	}, tree)
+
+	_, err = parseMask([]string{""})
+	require.Error(t, err)
+	_, err = parseMask([]string{"a..b"})
+	require.Error(t, err)
+}
+
+func TestStruct_ValidateMask(t *testing.T) {
+	s := New(&maskUser{})
+
+	require.NoError(t, s.ValidateMask([]string{"name", "address.city", "home.street", "note", "raw"}))
+	require.Error(t, s.ValidateMask([]string{"Password"}))
+	require.Error(t, s.ValidateMask([]string{"Name"}))
+	require.Error(t, s.ValidateMask([]string{"address.zip"}))
+	require.Error(t, s.ValidateMask([]string{"name.first"}))
+	require.Error(t, s.ValidateMask([]string{"raw.city"}))
+	require.Error(t, s.ValidateMask([]string{"Extra"}))
+	require.NoError(t, s.ValidateMask([]string{"tag"}))
+	require.Error(t, s.ValidateMask([]string{"More"}))
+	require.Error(t, s.ValidateMask([]string{""}))
+}
+
+func TestMapMasked(t *testing.T) {
+	u := &maskUser{
+		Name:     "gopher",
+		Password: "secret",
+		Address:  maskAddress{City: "shanghai", Street: "nanjing road"},
+		Extra:    maskExtra{Note: "n"},
+		More:     &maskMore{Tag: "t"},
+	}
+
+	got, err := MapMasked(u, []string{"name", "email", "address.city", "home.city", "note", "tag"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"name":    "gopher",
+		"address": map[string]interface{}{"city": "shanghai"},
+		"home":    (*maskAddress)(nil),
+		"note":    "n",
+		"tag":     "t",
+	}, got)
+
+	got, err = MapMasked(u, []string{"address"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"address": map[string]interface{}{"city": "shanghai", "street": "nanjing road"},
+	}, got)
+
+	_, err = MapMasked(u, []string{"password"})
+	require.Error(t, err)
+
+	type A struct {
+		Name string `json:"name"`
+	}
+	got, err = MapMaskedWithTag(A{"a"}, []string{"name"}, "json")
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"name": "a"}, got)
+}
+
+func TestApplyMask(t *testing.T) {
+	src := maskUser{
+		Name:     "new",
+		Email:    "new@golang.org",
+		Password: "new",
+		Address:  maskAddress{City: "beijing", Street: "chang'an"},
+		Home:     &maskAddress{City: "hangzhou", Street: "west lake"},
+		Extra:    maskExtra{Note: "new"},
+		More:     &maskMore{Tag: "new"},
+	}
+
+	t.Run("selected fields", func(t *testing.T) {
+		dst := maskUser{
+			Name:     "old",
+			Email:    "old@golang.org",
+			Password: "old",
+			Address:  maskAddress{City: "shanghai", Street: "nanjing road"},
+		}
+		require.NoError(t, ApplyMask(&dst, src, []string{"name", "address.city", "home.city", "note", "tag"}))
+		require.Equal(t, maskUser{
+			Name:     "new",
+			Email:    "old@golang.org",
+			Password: "old",
+			Address:  maskAddress{City: "beijing", Street: "nanjing road"},
+			Home:     &maskAddress{City: "hangzhou"},
+			Extra:    maskExtra{Note: "new"},
+			More:     &maskMore{Tag: "new"},
+		}, dst)
+	})
+	t.Run("nil source pointer", func(t *testing.T) {
+		dst := maskUser{Home: &maskAddress{City: "c", Street: "s"}, Extra: maskExtra{Note: "n"}, More: &maskMore{Tag: "t"}}
+		require.NoError(t, ApplyMask(&dst, &maskUser{}, []string{"home.city", "note", "tag"}))
+		require.Equal(t, maskUser{Home: &maskAddress{Street: "s"}, More: &maskMore{}}, dst)
+
+		dst = maskUser{Home: &maskAddress{City: "c"}}
+		require.NoError(t, ApplyMask(&dst, &maskUser{}, []string{"home"}))
+		require.Equal(t, maskUser{}, dst)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		dst := maskUser{Name: "old"}
+		require.Error(t, ApplyMask(dst, src, []string{"name"}))
+		require.Error(t, ApplyMask(&dst, maskAddress{}, []string{"name"}))
+		require.Error(t, ApplyMask(&dst, 1, []string{"name"}))
+		require.Error(t, ApplyMask(&dst, src, []string{"name", "password"}))
+		require.Equal(t, maskUser{Name: "old"}, dst)
+	})
+}
@@ -0,0 +1,302 @@
+package structs
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// Merger merges structs and maps into a struct, field by field.
+type Merger struct {
+	tagName      string
+	appendSlices bool
+	sources      map[string]int
+
+	timeFormat     TimeFormat
+	durationFormat DurationFormat
+}
+
+// NewMerger returns a new *Merger with the tag name of DefaultTagName.
+func NewMerger() *Merger {
+	return &Merger{
+		tagName: DefaultTagName,
+		sources: make(map[string]int),
+	}
+}
+
+// SetTagName set struct's field tag name, default is DefaultTagName.
+func (m *Merger) SetTagName(tagName string) *Merger {
+	m.tagName = tagName
+	return m
+}
+
+// SetAppendSlices sets whether slices of the sources are appended to the
+// slices of dst, instead of replacing them, default is false.
+func (m *Merger) SetAppendSlices(appendSlices bool) *Merger {
+	m.appendSlices = appendSlices
+	return m
+}
+
+// SetTimeFormat sets the format of the time.Time values of the sources,
+// which are parsed by ParseTime() if they are not time.Time, default is
+// TimeDefault. The option of "format" of a field overrides it.
+func (m *Merger) SetTimeFormat(format TimeFormat) *Merger {
+	m.timeFormat = format
+	return m
+}
+
+// SetDurationFormat sets the format of the time.Duration values of the
+// sources, which are parsed by ParseDuration(), the same as SetTimeFormat(),
+// default is DurationDefault.
+func (m *Merger) SetDurationFormat(format DurationFormat) *Merger {
+	m.durationFormat = format
+	return m
+}
+
+// Sources returns the index of the source, in the srcs of Merge, which
+// supplied each field of dst, keyed by the dotted path of tag names, ie:
+// "server.port". The fields of flattened structs are keyed by their own
+// names with the prefix of the option "prefix", the same as they appear in
+// Map().
+func (m *Merger) Sources() map[string]int {
+	return m.sources
+}
+
+// Merge merges srcs into dst in order, so later sources win. dst must be a
+// pointer to struct, each source a struct, pointer to struct, or map with
+// string keys, which are tag names. Nil sources are skipped. Only non-empty
+// values of the sources are merged, nested structs, and maps for nested
+// structs, are merged recursively. time.Time and time.Duration fields are
+// parsed from strings and numbers, see SetTimeFormat(). Other values are
+// assigned if they are assignable or convertible to the field of the same
+// kind, numbers to numbers which neither overflow the field nor lose their
+// fractional part, otherwise an error is returned, in which case dst may be
+// partly merged. The merge is shallow, the slices, maps and pointers, other
+// than the ones to nested structs, of the sources are assigned as is, so dst
+// shares them with the sources.
+func (m *Merger) Merge(dst interface{}, srcs ...interface{}) error {
+	dv := reflect.ValueOf(dst)
+	if dv.Kind() != reflect.Ptr || dv.IsNil() || dv.Elem().Kind() != reflect.Struct {
+		return errors.New("structs: dst must be a non-nil pointer to struct")
+	}
+	for i, src := range srcs {
+		get, err := m.getter(reflect.ValueOf(src))
+		if err != nil {
+			return fmt.Errorf("structs: merge source %d: %w", i, err)
+		}
+		if get == nil {
+			continue
+		}
+		if err = m.mergeStruct(dv.Elem(), get, i, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Merge merges srcs into dst in order. For more info refer to Merger types
+// Merge() method.
+func Merge(dst interface{}, srcs ...interface{}) error {
+	return NewMerger().Merge(dst, srcs...)
+}
+
+// MergeWithTag is the same as Merge() but with tagName.
+func MergeWithTag(dst interface{}, tagName string, srcs ...interface{}) error {
+	return NewMerger().SetTagName(tagName).Merge(dst, srcs...)
+}
+
+// getter returns the lookup of the values of src by tag name, which is nil
+// if src is nil.
+func (m *Merger) getter(src reflect.Value) (func(name string) (reflect.Value, bool), error) {
+	for src.Kind() == reflect.Ptr || src.Kind() == reflect.Interface {
+		if src.IsNil() {
+			return nil, nil
+		}
+		src = src.Elem()
+	}
+
+	switch src.Kind() { // nolint: exhaustive
+	case reflect.Invalid:
+		return nil, nil
+	case reflect.Struct:
+		return func(name string) (reflect.Value, bool) {
+			_, index, ok := lookupTagField(src.Type(), m.tagName, name)
+			if !ok {
+				return reflect.Value{}, false
+			}
+			return fieldByIndexZero(src, index), true
+		}, nil
+	case reflect.Map:
+		if src.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("unsupported map key type %s", src.Type().Key())
+		}
+		if src.IsNil() {
+			return nil, nil
+		}
+		return func(name string) (reflect.Value, bool) {
+			v := src.MapIndex(reflect.ValueOf(name).Convert(src.Type().Key()))
+			return v, v.IsValid()
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported type %s", src.Type())
+	}
+}
+
+func (m *Merger) mergeStruct(dst reflect.Value, get func(name string) (reflect.Value, bool), idx int, prefix string) error {
+	var err error
+	iteratorStructField(dst, m.tagName, func(field reflect.StructField) bool {
+		name, tagOpts := parseTag(field.Tag.Get(m.tagName))
+		if name == "" {
+			name = field.Name
+		}
+		fv := dst.FieldByIndex(field.Index)
+		if tagOpts.Contains("flatten") && !tagOpts.Contains("omitnested") && isMergeStruct(field.Type) {
+			keyPrefix := flattenPrefix(tagOpts)
+			target := fv
+			if fv.Kind() == reflect.Ptr {
+				if fv.IsNil() {
+					// a nil pointer is set only if anything is merged
+					target = reflect.New(fv.Type().Elem())
+				}
+				target = target.Elem()
+			}
+			err = m.mergeStruct(target, func(name string) (reflect.Value, bool) {
+				return get(keyPrefix + name)
+			}, idx, prefix+keyPrefix)
+			if err == nil && fv.Kind() == reflect.Ptr && fv.IsNil() && !isEmptyWithAll(target) {
+				fv.Set(target.Addr())
+			}
+			return err == nil
+		}
+		if sv, ok := get(name); ok {
+			err = m.mergeValue(fv, sv, idx, prefix+name, tagOpts)
+		}
+		return err == nil
+	})
+	return err
+}
+
+func (m *Merger) mergeValue(dst, src reflect.Value, idx int, path string, tagOpts tagOptions) error {
+	for src.Kind() == reflect.Interface && !src.IsNil() {
+		src = src.Elem()
+	}
+	if !src.IsValid() || isEmptyValue(src) || (src.Kind() == reflect.Struct && isEmptyWithAll(src)) {
+		return nil
+	}
+
+	if isMergeStruct(dst.Type()) && !tagOpts.Contains("omitnested") {
+		get, err := m.getter(src)
+		if err != nil {
+			return fmt.Errorf("structs: merge field %q: %w", path, err)
+		}
+		if get == nil {
+			return nil
+		}
+		target := dst
+		if dst.Kind() == reflect.Ptr {
+			if dst.IsNil() {
+				dst.Set(reflect.New(dst.Type().Elem()))
+			}
+			target = dst.Elem()
+		}
+		return m.mergeStruct(target, get, idx, path+".")
+	}
+
+	tf, df := m.timeFormat, m.durationFormat
+	if format, ok := tagOpts.Value("format"); ok {
+		tf, df = TimeFormat(format), DurationFormat(format)
+	}
+	src, ok, err := parseTimeValue(dst.Type(), src, tf, df)
+	if err != nil {
+		return fmt.Errorf("structs: merge field %q: %w", path, err)
+	}
+	if !ok && !src.Type().AssignableTo(dst.Type()) {
+		if !isConvertible(src.Type(), dst.Type()) {
+			return fmt.Errorf("structs: merge field %q: cannot assign %s to %s", path, src.Type(), dst.Type())
+		}
+		if err = checkNumber(src, dst.Type()); err != nil {
+			return fmt.Errorf("structs: merge field %q: %w", path, err)
+		}
+		src = src.Convert(dst.Type())
+	}
+	if dst.Kind() == reflect.Slice && m.appendSlices && dst.Len() > 0 {
+		out := reflect.MakeSlice(dst.Type(), 0, dst.Len()+src.Len())
+		src = reflect.AppendSlice(reflect.AppendSlice(out, dst), src)
+	}
+	dst.Set(src)
+	m.sources[path] = idx
+	return nil
+}
+
+// isMergeStruct reports whether the values of t, a struct or pointer to
+// struct with exported fields, are merged field by field.
+func isMergeStruct(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).PkgPath == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// isConvertible reports whether the values of src are converted to dst,
+// which must be of the same kind, or numbers.
+func isConvertible(src, dst reflect.Type) bool {
+	if !src.ConvertibleTo(dst) {
+		return false
+	}
+	return src.Kind() == dst.Kind() || (isNumberKind(src.Kind()) && isNumberKind(dst.Kind()))
+}
+
+// checkNumber returns an error if the number v overflows the number type t,
+// or loses its fractional part converted to an integer type.
+func checkNumber(v reflect.Value, t reflect.Type) error {
+	if !isNumberKind(v.Kind()) || !isNumberKind(t.Kind()) {
+		return nil
+	}
+	zero := reflect.Zero(t)
+	overflow := false
+	switch t.Kind() { // nolint: exhaustive
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch v.Kind() { // nolint: exhaustive
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			overflow = zero.OverflowInt(v.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			overflow = v.Uint() > math.MaxInt64 || zero.OverflowInt(int64(v.Uint()))
+		default:
+			f := v.Float()
+			if f != math.Trunc(f) {
+				return fmt.Errorf("structs: %v loses its fraction in %s", f, t)
+			}
+			overflow = f < math.MinInt64 || f >= math.MaxInt64 || zero.OverflowInt(int64(f))
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		switch v.Kind() { // nolint: exhaustive
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			overflow = v.Int() < 0 || zero.OverflowUint(uint64(v.Int()))
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			overflow = zero.OverflowUint(v.Uint())
+		default:
+			f := v.Float()
+			if f != math.Trunc(f) {
+				return fmt.Errorf("structs: %v loses its fraction in %s", f, t)
+			}
+			overflow = f < 0 || f >= math.MaxUint64 || zero.OverflowUint(uint64(f))
+		}
+	case reflect.Float32:
+		if k := v.Kind(); k == reflect.Float32 || k == reflect.Float64 {
+			overflow = zero.OverflowFloat(v.Float())
+		}
+	}
+	if overflow {
+		return fmt.Errorf("structs: %v overflows %s", v.Interface(), t)
+	}
+	return nil
+}
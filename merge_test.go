@@ -0,0 +1,141 @@
+package structs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type mergeServer struct {
+	Host string `map:"host"`
+	Port int    `map:"port"`
+}
+
+type mergeLog struct {
+	Level string `map:"level"`
+}
+
+type mergeConfig struct {
+	Name    string        `map:"name"`
+	Debug   bool          `map:"debug"`
+	Timeout time.Duration `map:"timeout"`
+	Since   time.Time     `map:"since"`
+	Server  mergeServer   `map:"server"`
+	Backup  *mergeServer  `map:"backup"`
+	Tags    []string      `map:"tags"`
+	Log     mergeLog      `map:",flatten"`
+	Ignored string        `map:"-"`
+}
+
+func TestMerge(t *testing.T) {
+	since := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	defaults := mergeConfig{
+		Name:    "app",
+		Timeout: time.Second,
+		Server:  mergeServer{Host: "localhost", Port: 80},
+		Tags:    []string{"default"},
+		Log:     mergeLog{Level: "info"},
+	}
+	file := &mergeConfig{
+		Server: mergeServer{Port: 8080},
+		Since:  since,
+		Tags:   []string{"file"},
+	}
+	env := map[string]interface{}{
+		"debug":   true,
+		"timeout": int64(2 * time.Second),
+		"backup":  map[string]interface{}{"host": "backup"},
+		"level":   "debug",
+		"name":    "",
+		"Ignored": "ignored",
+	}
+	var nilConfig *mergeConfig
+
+	var got mergeConfig
+	m := NewMerger()
+	require.NoError(t, m.Merge(&got, defaults, file, nilConfig, nil, env))
+	require.Equal(t, mergeConfig{
+		Name:    "app",
+		Debug:   true,
+		Timeout: 2 * time.Second,
+		Since:   since,
+		Server:  mergeServer{Host: "localhost", Port: 8080},
+		Backup:  &mergeServer{Host: "backup"},
+		Tags:    []string{"file"},
+		Log:     mergeLog{Level: "debug"},
+	}, got)
+	require.Equal(t, map[string]int{
+		"name":        0,
+		"debug":       4,
+		"timeout":     4,
+		"since":       1,
+		"server.host": 0,
+		"server.port": 1,
+		"backup.host": 4,
+		"tags":        1,
+		"level":       4,
+	}, m.Sources())
+}
+
+func TestMerger_SetAppendSlices(t *testing.T) {
+	dst := mergeConfig{Tags: []string{"a"}}
+	tags := dst.Tags
+	require.NoError(t, NewMerger().SetAppendSlices(true).Merge(&dst,
+		mergeConfig{Tags: []string{"b"}},
+		map[string][]string{"tags": {"c"}},
+	))
+	require.Equal(t, []string{"a", "b", "c"}, dst.Tags)
+	require.Equal(t, []string{"a"}, tags)
+}
+
+func TestMerge_FlattenPointer(t *testing.T) {
+	type config struct {
+		Name string    `map:"name"`
+		Log  *mergeLog `map:",flatten"`
+	}
+
+	var got config
+	require.NoError(t, Merge(&got, config{Name: "app"}, map[string]interface{}{"name": "x"}))
+	require.Equal(t, config{Name: "x"}, got)
+
+	require.NoError(t, Merge(&got, config{Log: &mergeLog{Level: "info"}}))
+	require.Equal(t, config{Name: "x", Log: &mergeLog{Level: "info"}}, got)
+
+	log := got.Log
+	require.NoError(t, Merge(&got, map[string]interface{}{"level": "debug"}))
+	require.Equal(t, config{Name: "x", Log: &mergeLog{Level: "debug"}}, got)
+	require.Same(t, log, got.Log)
+}
+
+func TestMerge_Error(t *testing.T) {
+	var dst mergeConfig
+	require.Error(t, Merge(dst, mergeConfig{}))
+	require.Error(t, Merge(&dst, 1))
+	require.Error(t, Merge(&dst, map[int]interface{}{}))
+	require.Error(t, Merge(&dst, map[string]interface{}{"port": 1, "server": map[string]interface{}{"port": "80"}}))
+	require.Error(t, Merge(&dst, map[string]interface{}{"name": 1}))
+	require.Error(t, Merge(&dst, map[string]interface{}{"server": 1}))
+
+	type N struct {
+		Port int8 `map:"port"`
+		Rate int  `map:"rate"`
+		Size uint `map:"size"`
+	}
+	var n N
+	require.EqualError(t, Merge(&n, map[string]interface{}{"port": 300}),
+		`structs: merge field "port": structs: 300 overflows int8`)
+	require.EqualError(t, Merge(&n, map[string]interface{}{"rate": 1.5}),
+		`structs: merge field "rate": structs: 1.5 loses its fraction in int`)
+	require.EqualError(t, Merge(&n, map[string]interface{}{"size": -1}),
+		`structs: merge field "size": structs: -1 overflows uint`)
+	require.NoError(t, Merge(&n, map[string]interface{}{"port": 100, "rate": 2.0, "size": int64(3)}))
+	require.Equal(t, N{Port: 100, Rate: 2, Size: 3}, n)
+
+	type A struct {
+		Name string `json:"name"`
+	}
+	var a A
+	require.NoError(t, MergeWithTag(&a, "json", map[string]interface{}{"name": "a"}))
+	require.Equal(t, A{"a"}, a)
+}
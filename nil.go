@@ -0,0 +1,92 @@
+package structs
+
+import (
+	"errors"
+	"reflect"
+	"sync/atomic"
+)
+
+var errNilStruct = errors.New("structs: nil pointer to struct")
+
+// NilPolicy is the way nil pointers to structs are handled.
+type NilPolicy int32
+
+// NilPolicy list
+const (
+	// NilDefault uses the policy of the Struct, or the global policy set by
+	// SetNilPolicy.
+	NilDefault NilPolicy = iota
+	// NilAsNil keeps nil pointers as nil.
+	NilAsNil
+	// NilOmit omits nil pointers.
+	NilOmit
+	// NilAsZero expands nil pointers to the zero value of the struct.
+	NilAsZero
+)
+
+var nilPolicy = int32(NilAsNil)
+
+// SetNilPolicy sets the global nil policy, which is used by every Struct
+// without its own policy, and by New and MapSlice, default is NilAsNil.
+func SetNilPolicy(policy NilPolicy) {
+	if policy == NilDefault {
+		policy = NilAsNil
+	}
+	atomic.StoreInt32(&nilPolicy, int32(policy))
+}
+
+// SetNilPolicy sets the nil policy of the struct, which overrides the global
+// policy set by SetNilPolicy. It applies to the fields, and to the elements of
+// slices and values of maps in nested output, which are nil pointers to
+// structs, in the output of Map, Values, Names, IsZero and HasZero, and of
+// nested structs as well. Example:
+//
+//   type T struct {
+//       Address *Address `map:"address"`
+//   }
+//
+//   // NilAsNil:  map[address:(*Address)(nil)]
+//   // NilOmit:   map[]
+//   // NilAsZero: map[address:map[city: zip:]]
+//
+// With NilAsZero, New and MapSlice expand nil pointers to zero structs as
+// well, otherwise New panics and MapSlice outputs nil maps, or omits them
+// with NilOmit.
+func (s *Struct) SetNilPolicy(policy NilPolicy) *Struct {
+	s.nilPolicy = policy
+	return s
+}
+
+// resolveNilPolicy returns the policy of the struct, or the global policy.
+func resolveNilPolicy(policy NilPolicy) NilPolicy {
+	if policy == NilDefault {
+		return NilPolicy(atomic.LoadInt32(&nilPolicy))
+	}
+	return policy
+}
+
+// nilValue returns v with the nil policy of s, which is a pointer to the zero
+// struct if v is a nil pointer to struct and the policy is NilAsZero, the
+// boolean returns false if v is omitted.
+func (s *Struct) nilValue(v reflect.Value) (reflect.Value, bool) {
+	return applyNilPolicy(v, s.nilPolicy)
+}
+
+func applyNilPolicy(v reflect.Value, policy NilPolicy) (reflect.Value, bool) {
+	if !isNilStruct(v) {
+		return v, true
+	}
+	switch resolveNilPolicy(policy) { // nolint: exhaustive
+	case NilOmit:
+		return v, false
+	case NilAsZero:
+		return reflect.New(v.Type().Elem()), true
+	default:
+		return v, true
+	}
+}
+
+// isNilStruct reports whether v is a nil pointer to struct.
+func isNilStruct(v reflect.Value) bool {
+	return v.Kind() == reflect.Ptr && v.IsNil() && v.Type().Elem().Kind() == reflect.Struct
+}
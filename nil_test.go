@@ -0,0 +1,99 @@
+package structs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type nilAddress struct {
+	City string `map:"city"`
+}
+
+type nilUser struct {
+	Name    string                 `map:"name"`
+	Home    *nilAddress            `map:"home"`
+	Work    *nilAddress            `map:"work,omitempty"`
+	Others  []*nilAddress          `map:"others"`
+	ByName  map[string]*nilAddress `map:"by_name"`
+	Current *nilAddress            `map:"current"`
+}
+
+func TestStruct_SetNilPolicy(t *testing.T) {
+	current := &nilAddress{City: "c"}
+	u := nilUser{
+		Name:    "n",
+		Others:  []*nilAddress{nil, current},
+		ByName:  map[string]*nilAddress{"a": nil, "b": current},
+		Current: current,
+	}
+	mCurrent := map[string]interface{}{"city": "c"}
+	mZero := map[string]interface{}{"city": ""}
+
+	require.Equal(t, map[string]interface{}{
+		"name":    "n",
+		"home":    (*nilAddress)(nil),
+		"others":  []interface{}{(*nilAddress)(nil), mCurrent},
+		"by_name": map[string]interface{}{"a": (*nilAddress)(nil), "b": mCurrent},
+		"current": mCurrent,
+	}, New(u).SetNilPolicy(NilAsNil).Map())
+	require.Equal(t, []interface{}{"n", (*nilAddress)(nil), u.Others, u.ByName, "c"}, New(u).Values())
+	require.Equal(t, []string{"Name", "Home", "Work", "Others", "ByName", "Current"}, New(u).Names())
+
+	require.Equal(t, map[string]interface{}{
+		"name":    "n",
+		"others":  []interface{}{mCurrent},
+		"by_name": map[string]interface{}{"b": mCurrent},
+		"current": mCurrent,
+	}, New(u).SetNilPolicy(NilOmit).Map())
+	require.Equal(t, []interface{}{"n", u.Others, u.ByName, "c"}, New(u).SetNilPolicy(NilOmit).Values())
+	require.Equal(t, []string{"Name", "Others", "ByName", "Current"}, New(u).SetNilPolicy(NilOmit).Names())
+
+	require.Equal(t, map[string]interface{}{
+		"name":    "n",
+		"home":    mZero,
+		"others":  []interface{}{mZero, mCurrent},
+		"by_name": map[string]interface{}{"a": mZero, "b": mCurrent},
+		"current": mCurrent,
+	}, New(u).SetNilPolicy(NilAsZero).Map())
+	require.Equal(t, []interface{}{"n", "", u.Others, u.ByName, "c"}, New(u).SetNilPolicy(NilAsZero).Values())
+
+	m := New(u).SetNilPolicy(NilOmit).MapNormalized()
+	require.Equal(t, []interface{}{mCurrent}, m["others"])
+	m = New(u).SetNilPolicy(NilAsZero).MapNormalized()
+	require.Equal(t, []interface{}{mZero, mCurrent}, m["others"])
+}
+
+func TestStruct_NilPolicyZero(t *testing.T) {
+	type T struct {
+		Home *nilAddress `map:"home"`
+	}
+	for _, policy := range []NilPolicy{NilAsNil, NilOmit, NilAsZero} {
+		require.True(t, New(T{}).SetNilPolicy(policy).IsZero())
+	}
+	require.True(t, New(T{}).SetNilPolicy(NilAsNil).HasZero())
+	require.False(t, New(T{}).SetNilPolicy(NilOmit).HasZero())
+	require.True(t, New(T{}).SetNilPolicy(NilAsZero).HasZero())
+	require.False(t, New(T{Home: &nilAddress{City: "c"}}).SetNilPolicy(NilOmit).IsZero())
+}
+
+func TestSetNilPolicy(t *testing.T) {
+	var nilAddr *nilAddress
+	s := []*nilAddress{nil, {City: "c"}}
+
+	require.PanicsWithValue(t, "structs: nil pointer to struct *structs.nilAddress", func() { New(nilAddr) })
+	require.Equal(t, []map[string]interface{}{nil, {"city": "c"}}, MapSlice(s))
+
+	defer SetNilPolicy(NilDefault)
+	SetNilPolicy(NilOmit)
+	require.Equal(t, []map[string]interface{}{{"city": "c"}}, MapSlice(s))
+	require.NotContains(t, Map(nilUser{}), "home")
+
+	SetNilPolicy(NilAsZero)
+	require.Equal(t, []map[string]interface{}{{"city": ""}, {"city": "c"}}, MapSlice(s))
+	require.Equal(t, map[string]interface{}{"city": ""}, Map(nilAddr))
+	require.Equal(t, "", New(nilUser{}).Map()["home"].(map[string]interface{})["city"])
+
+	SetNilPolicy(NilDefault)
+	require.Equal(t, (*nilAddress)(nil), Map(nilUser{})["home"])
+}
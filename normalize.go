@@ -0,0 +1,170 @@
+package structs
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// scalarTypes are the predeclared types of the scalar kinds.
+var scalarTypes = map[reflect.Kind]reflect.Type{
+	reflect.Bool:       reflect.TypeOf(false),
+	reflect.Int:        reflect.TypeOf(int(0)),
+	reflect.Int8:       reflect.TypeOf(int8(0)),
+	reflect.Int16:      reflect.TypeOf(int16(0)),
+	reflect.Int32:      reflect.TypeOf(int32(0)),
+	reflect.Int64:      reflect.TypeOf(int64(0)),
+	reflect.Uint:       reflect.TypeOf(uint(0)),
+	reflect.Uint8:      reflect.TypeOf(uint8(0)),
+	reflect.Uint16:     reflect.TypeOf(uint16(0)),
+	reflect.Uint32:     reflect.TypeOf(uint32(0)),
+	reflect.Uint64:     reflect.TypeOf(uint64(0)),
+	reflect.Uintptr:    reflect.TypeOf(uintptr(0)),
+	reflect.Float32:    reflect.TypeOf(float32(0)),
+	reflect.Float64:    reflect.TypeOf(float64(0)),
+	reflect.Complex64:  reflect.TypeOf(complex64(0)),
+	reflect.Complex128: reflect.TypeOf(complex128(0)),
+	reflect.String:     reflect.TypeOf(""),
+}
+
+// SetNormalize sets whether the values of the fields are fully converted to
+// JSON like values in the output of Map(), default is false. Example:
+//
+//   // Items appears in map as []interface{}{[]interface{}{map[string]interface{}{...}}}.
+//   Items [][]Item `map:"items"`
+//
+//   // Level appears in map as string.
+//   Level Level `map:"level"`
+//
+// Normalized values are nil, map[string]interface{}, []interface{}, []byte
+// and the predeclared scalar types. Structs are converted by Map(), except the
+// structs without exported fields, which are converted by their
+// encoding.TextMarshaler or fmt.Stringer methods, time.Time as time.RFC3339Nano
+// unless SetTimeFormat() is set, or to empty maps. Maps of any element type are
+// converted with the keys converted by MapKey(), see SetKeyEncoder(), and
+// slices, arrays, pointers and interfaces of any element type recursively.
+// Other values which implement encoding.TextMarshaler are converted by it,
+// named scalar types to their underlying predeclared types, and channels and
+// functions to nil. The fields with the option of "omitnested" are left as is.
+func (s *Struct) SetNormalize(normalize bool) *Struct {
+	s.normalize = normalize
+	return s
+}
+
+// MapNormalized is the same as Map, but the values are fully normalized, the
+// setting of s is not changed. For more info refer to Struct types
+// SetNormalize() method.
+func (s *Struct) MapNormalized() map[string]interface{} {
+	return s.clone().SetNormalize(true).Map()
+}
+
+// MapNormalized converts the given struct to a map[string]interface{} with
+// fully normalized values. For more info refer to Struct types SetNormalize()
+// method. It panics if s's kind is not struct.
+func MapNormalized(s interface{}) map[string]interface{} {
+	return New(s).MapNormalized()
+}
+
+// normalized returns the normalized value of v, see SetNormalize.
+func (s *Struct) normalized(v reflect.Value) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+	if tv, ok := s.timeValue(v); ok {
+		return tv
+	}
+
+	switch v.Kind() { // nolint: exhaustive
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return s.normalized(v.Elem())
+	case reflect.Struct:
+		if m := s.inherit(v.Interface()).Map(); len(m) > 0 || isMergeStruct(v.Type()) {
+			return m
+		}
+		return normalizedText(v)
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		m := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			if e, ok := s.nilValue(iter.Value()); ok {
+				m[s.keyString(iter.Key())] = s.normalized(e)
+			}
+		}
+		return m
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		if text, ok := marshalText(v); ok {
+			return text
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(b), v)
+			return b
+		}
+		slice := make([]interface{}, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			if e, ok := s.nilValue(v.Index(i)); ok {
+				slice = append(slice, s.normalized(e))
+			}
+		}
+		return slice
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return nil
+	default:
+		if text, ok := marshalText(v); ok {
+			return text
+		}
+		if t, ok := scalarTypes[v.Kind()]; ok && v.Type() != t {
+			return v.Convert(t).Interface()
+		}
+		return v.Interface()
+	}
+}
+
+// marshalText returns the text of v if it implements encoding.TextMarshaler
+// without error.
+func marshalText(v reflect.Value) (string, bool) {
+	if !v.CanInterface() {
+		return "", false
+	}
+	tm, ok := v.Interface().(encoding.TextMarshaler)
+	if !ok {
+		return "", false
+	}
+	text, err := tm.MarshalText()
+	if err != nil {
+		return "", false
+	}
+	return string(text), true
+}
+
+// normalizedText returns the struct v without exported fields as string, by
+// its methods, or an empty map.
+func normalizedText(v reflect.Value) interface{} {
+	x := v.Interface()
+	if v.CanAddr() {
+		x = v.Addr().Interface()
+	}
+	switch t := x.(type) {
+	case time.Time:
+		return t.Format(time.RFC3339Nano)
+	case *time.Time:
+		return t.Format(time.RFC3339Nano)
+	case encoding.TextMarshaler:
+		if text, err := t.MarshalText(); err == nil {
+			return string(text)
+		}
+	case fmt.Stringer:
+		return t.String()
+	}
+	return map[string]interface{}{}
+}
@@ -0,0 +1,93 @@
+package structs
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type normalizeLevel string
+
+type normalizeItem struct {
+	Name  string         `map:"name"`
+	Level normalizeLevel `map:"level"`
+}
+
+type normalizeAll struct {
+	Grid    [][]normalizeItem                   `map:"grid"`
+	Nested  map[string]map[string]normalizeItem `map:"nested"`
+	Any     map[string]interface{}              `map:"any"`
+	List    []interface{}                       `map:"list"`
+	Ptr     *normalizeItem                      `map:"ptr"`
+	Nil     *normalizeItem                      `map:"nil"`
+	Level   normalizeLevel                      `map:"level"`
+	Timeout time.Duration                       `map:"timeout"`
+	At      time.Time                           `map:"at"`
+	IP      net.IP                              `map:"ip"`
+	Bytes   []byte                              `map:"bytes"`
+	Empty   []int                               `map:"empty"`
+	Fn      func()                              `map:"fn"`
+	Raw     normalizeItem                       `map:"raw,omitnested"`
+	Items   []normalizeItem                     `map:",flatten"`
+}
+
+func TestStruct_SetNormalize(t *testing.T) {
+	at := time.Date(2021, 1, 2, 3, 4, 5, 6, time.UTC)
+	item := normalizeItem{Name: "a", Level: "x"}
+	mItem := map[string]interface{}{"name": "a", "level": "x"}
+	v := normalizeAll{
+		Grid:    [][]normalizeItem{{item}},
+		Nested:  map[string]map[string]normalizeItem{"k": {"kk": item}},
+		Any:     map[string]interface{}{"item": item, "n": 1},
+		List:    []interface{}{&item, "s", nil},
+		Ptr:     &item,
+		Level:   "y",
+		Timeout: time.Second,
+		At:      at,
+		IP:      net.IPv4(127, 0, 0, 1),
+		Bytes:   []byte("b"),
+		Fn:      func() {},
+		Raw:     item,
+		Items:   []normalizeItem{item},
+	}
+
+	m := Map(v)
+	require.Equal(t, v.Grid, m["grid"])
+	require.Equal(t, v.Any, m["any"])
+
+	require.Equal(t, map[string]interface{}{
+		"grid":    []interface{}{[]interface{}{mItem}},
+		"nested":  map[string]interface{}{"k": map[string]interface{}{"kk": mItem}},
+		"any":     map[string]interface{}{"item": mItem, "n": 1},
+		"list":    []interface{}{mItem, "s", nil},
+		"ptr":     mItem,
+		"nil":     nil,
+		"level":   "y",
+		"timeout": int64(time.Second),
+		"at":      "2021-01-02T03:04:05.000000006Z",
+		"ip":      "127.0.0.1",
+		"bytes":   []byte("b"),
+		"empty":   nil,
+		"fn":      nil,
+		"raw":     item,
+		"Items":   []interface{}{mItem},
+	}, MapNormalized(v))
+
+	m = New(v).SetTimeFormat(TimeUnix).SetDurationFormat(DurationString).MapNormalized()
+	require.Equal(t, at.Unix(), m["at"])
+	require.Equal(t, "1s", m["timeout"])
+
+	m = New(struct {
+		ByID map[int][]int `map:"by_id"`
+	}{map[int][]int{1: {2}}}).MapNormalized()
+	require.Equal(t, map[string]interface{}{"1": []interface{}{2}}, m["by_id"])
+
+	// the struct is not normalized after
+	s := New(struct {
+		Level normalizeLevel `map:"level"`
+	}{Level: "debug"})
+	require.Equal(t, "debug", s.MapNormalized()["level"])
+	require.Equal(t, normalizeLevel("debug"), s.Map()["level"])
+}
@@ -0,0 +1,352 @@
+package structs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// RedactMask is the mask replacing the value of a sensitive field.
+const RedactMask = "******"
+
+// RedactMode is the way the value of a sensitive field is masked.
+type RedactMode int32
+
+// RedactMode list
+const (
+	// RedactDefault uses the mode of the Struct, or the global mode set by
+	// SetRedactMode.
+	RedactDefault RedactMode = iota
+	// RedactFull replaces the value with RedactMask.
+	RedactFull
+	// RedactLast4 replaces the value with RedactMask followed by the last
+	// 4 characters of the value.
+	RedactLast4
+	// RedactHash replaces the value with "sha256:" followed by the first 16
+	// hex characters of the SHA-256 hash of the value.
+	RedactHash
+	// RedactNone disables the redaction.
+	RedactNone
+)
+
+var redactMode = int32(RedactFull)
+
+// SetRedactMode sets the global redaction mode, which is used by every Struct
+// without its own mode, default is RedactFull. RedactNone disables the
+// redaction globally.
+func SetRedactMode(mode RedactMode) {
+	if mode == RedactDefault {
+		mode = RedactFull
+	}
+	atomic.StoreInt32(&redactMode, int32(mode))
+}
+
+// SetRedactMode sets the redaction mode of the struct, which overrides the
+// global mode set by SetRedactMode. A struct tag with the option of "redact"
+// or "sensitive" marks a sensitive field, whose value is masked in the output
+// of Map and Values, in nested structs as well. The option may choose the mode
+// of the field, which is ignored if redaction is disabled. Example:
+//
+//   // Field appears in map as "******".
+//   Password string `map:"password,redact"`
+//
+//   // Field appears in map as "******1234".
+//   CardNumber string `map:"card_number,redact=last4"`
+//
+//   // Field appears in map as "sha256:..."
+//   Token string `map:"token,sensitive=hash"`
+//
+// Empty values are not masked.
+func (s *Struct) SetRedactMode(mode RedactMode) *Struct {
+	s.redact = mode
+	return s
+}
+
+// ParseRedactMode parses the mode of the option of "redact", which is "full",
+// "last4", "hash" or "none". An empty string is RedactDefault.
+func ParseRedactMode(mode string) (RedactMode, error) {
+	switch mode {
+	case "":
+		return RedactDefault, nil
+	case "full":
+		return RedactFull, nil
+	case "last4":
+		return RedactLast4, nil
+	case "hash":
+		return RedactHash, nil
+	case "none":
+		return RedactNone, nil
+	default:
+		return RedactDefault, fmt.Errorf("structs: invalid redact mode %q", mode)
+	}
+}
+
+// Mask returns the masked v with mode, which falls back to the global mode if
+// it is RedactDefault. It returns v as is if v is empty or the redaction is
+// disabled globally.
+func Mask(v interface{}, mode RedactMode) interface{} {
+	return maskValue(reflect.ValueOf(v), resolveRedactMode(mode, RedactDefault))
+}
+
+// resolveRedactMode returns the mode of a sensitive field with the mode of the
+// field tag and the mode of the struct.
+func resolveRedactMode(field, structMode RedactMode) RedactMode {
+	policy := structMode
+	if policy == RedactDefault {
+		policy = RedactMode(atomic.LoadInt32(&redactMode))
+	}
+	if policy == RedactNone || field == RedactDefault {
+		return policy
+	}
+	return field
+}
+
+// redactMode returns the mode of the field with the tag options, the boolean
+// returns true if the field is sensitive and the redaction is enabled.
+func (s *Struct) redactMode(tagOpts tagOptions) (RedactMode, bool) {
+	field, ok := fieldRedactMode(tagOpts)
+	if !ok {
+		return RedactNone, false
+	}
+	mode := resolveRedactMode(field, s.redact)
+	return mode, mode != RedactNone
+}
+
+// fieldRedactMode returns the mode of the option of "redact" or "sensitive",
+// the boolean returns true if the field is sensitive. An invalid mode is
+// handled as RedactFull.
+func fieldRedactMode(tagOpts tagOptions) (RedactMode, bool) {
+	for _, name := range []string{"redact", "sensitive"} {
+		if tagOpts.Contains(name) {
+			return RedactDefault, true
+		}
+		if v, ok := tagOpts.Value(name); ok {
+			mode, err := ParseRedactMode(v)
+			if err != nil {
+				mode = RedactFull
+			}
+			return mode, true
+		}
+	}
+	return RedactDefault, false
+}
+
+func maskValue(v reflect.Value, mode RedactMode) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+	if mode == RedactNone || isEmptyValue(v) {
+		return v.Interface()
+	}
+	switch mode { // nolint: exhaustive
+	case RedactLast4:
+		r := []rune(maskString(v))
+		if len(r) <= 4 {
+			return RedactMask
+		}
+		return RedactMask + string(r[len(r)-4:])
+	case RedactHash:
+		sum := sha256.Sum256([]byte(maskString(v)))
+		return "sha256:" + hex.EncodeToString(sum[:8])
+	default:
+		return RedactMask
+	}
+}
+
+func maskString(v reflect.Value) string {
+	v = reflect.Indirect(v)
+	switch {
+	case v.Kind() == reflect.String:
+		return v.String()
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8:
+		return string(v.Bytes())
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
+
+// Redacted returns a deep copy of the struct s, or pointer to struct, with the
+// sensitive fields masked, which is safe for logging with "%v". For more info
+// refer to RedactedWithTag() function.
+func Redacted(s interface{}) interface{} {
+	return RedactedWithTag(s, DefaultTagName)
+}
+
+// RedactedWithTag returns a deep copy of s with the sensitive fields with
+// tagName masked, see Struct types SetRedactMode() method. Sensitive string
+// fields hold the mask, other sensitive fields are set to their zero value.
+// Nested structs, pointers, slices, arrays, maps and interfaces are copied
+// and masked recursively, unexported fields are copied as is. It returns s as
+// is if s holds no sensitive fields or the redaction is disabled globally.
+func RedactedWithTag(s interface{}, tagName string) interface{} {
+	if s == nil {
+		return nil
+	}
+	return redactValue(reflect.ValueOf(s), tagName, RedactDefault)
+}
+
+// redacted returns the redacted copy of the field value v, see
+// RedactedWithTag.
+func (s *Struct) redacted(v reflect.Value) interface{} {
+	return redactValue(v, s.tagName, s.redact)
+}
+
+func redactValue(v reflect.Value, tagName string, mode RedactMode) interface{} {
+	if resolveRedactMode(RedactDefault, mode) == RedactNone ||
+		!hasSensitiveValue(v, tagName, make(map[sensitivePtr]bool)) {
+		return v.Interface()
+	}
+	c := &cloner{seen: make(map[cloneKey]reflect.Value)}
+	c.field = func(f reflect.StructField, dst, src reflect.Value) bool {
+		_, tagOpts := parseTag(f.Tag.Get(tagName))
+		field, ok := fieldRedactMode(tagOpts)
+		if !ok {
+			return false
+		}
+		if field = resolveRedactMode(field, mode); field == RedactNone {
+			return false
+		}
+		maskField(dst, src, field)
+		return true
+	}
+	return c.copy(v).Interface()
+}
+
+type sensitiveKey struct {
+	typ     reflect.Type
+	tagName string
+}
+
+// sensitivity is whether the values of a type hold sensitive fields.
+type sensitivity int
+
+const (
+	notSensitive sensitivity = iota
+	// dynamicSensitive types hold sensitive fields only if the values of
+	// their interfaces do.
+	dynamicSensitive
+	staticSensitive
+)
+
+// sensitiveTypes caches the result of typeSensitivity.
+var sensitiveTypes sync.Map // sensitiveKey -> sensitivity
+
+// typeSensitivity returns whether the values of t hold sensitive fields with
+// tagName.
+func typeSensitivity(t reflect.Type, tagName string) sensitivity {
+	key := sensitiveKey{t, tagName}
+	if v, ok := sensitiveTypes.Load(key); ok {
+		return v.(sensitivity)
+	}
+	sensitive := hasSensitiveType(t, tagName, make(map[reflect.Type]bool))
+	sensitiveTypes.Store(key, sensitive)
+	return sensitive
+}
+
+func hasSensitiveType(t reflect.Type, tagName string, seen map[reflect.Type]bool) sensitivity {
+	if seen[t] {
+		return notSensitive
+	}
+	seen[t] = true
+	switch t.Kind() { // nolint: exhaustive
+	case reflect.Struct:
+		sensitive := notSensitive
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			_, tagOpts := parseTag(field.Tag.Get(tagName))
+			if _, ok := fieldRedactMode(tagOpts); ok {
+				return staticSensitive
+			}
+			switch hasSensitiveType(field.Type, tagName, seen) {
+			case staticSensitive:
+				return staticSensitive
+			case dynamicSensitive:
+				sensitive = dynamicSensitive
+			}
+		}
+		return sensitive
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return hasSensitiveType(t.Elem(), tagName, seen)
+	case reflect.Interface:
+		return dynamicSensitive
+	default:
+		return notSensitive
+	}
+}
+
+type sensitivePtr struct {
+	typ reflect.Type
+	ptr uintptr
+	len int
+}
+
+// hasSensitiveValue reports whether v holds sensitive fields with tagName,
+// looking into the values of interfaces only if the type of v can't tell.
+func hasSensitiveValue(v reflect.Value, tagName string, seen map[sensitivePtr]bool) bool {
+	switch typeSensitivity(v.Type(), tagName) {
+	case notSensitive:
+		return false
+	case staticSensitive:
+		return true
+	}
+	switch v.Kind() { // nolint: exhaustive
+	case reflect.Interface:
+		return !v.IsNil() && hasSensitiveValue(v.Elem(), tagName, seen)
+	case reflect.Ptr, reflect.Map, reflect.Slice:
+		if v.IsNil() {
+			return false
+		}
+		key := sensitivePtr{typ: v.Type(), ptr: v.Pointer()}
+		if v.Kind() == reflect.Slice {
+			key.len = v.Len()
+		}
+		if seen[key] {
+			return false
+		}
+		seen[key] = true
+	}
+	switch v.Kind() { // nolint: exhaustive
+	case reflect.Ptr:
+		return hasSensitiveValue(v.Elem(), tagName, seen)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath == "" && hasSensitiveValue(v.Field(i), tagName, seen) {
+				return true
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if hasSensitiveValue(v.Index(i), tagName, seen) {
+				return true
+			}
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			if hasSensitiveValue(iter.Value(), tagName, seen) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// maskField sets the sensitive field dst to the masked value of src, or zero
+// value if the field can't hold a string.
+func maskField(dst, src reflect.Value, mode RedactMode) {
+	if isEmptyValue(src) {
+		dst.Set(src)
+		return
+	}
+	if dst.Kind() == reflect.String {
+		dst.Set(reflect.ValueOf(maskValue(src, mode)).Convert(dst.Type()))
+		return
+	}
+	dst.Set(reflect.Zero(dst.Type()))
+}
@@ -0,0 +1,203 @@
+package structs
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type redactCard struct {
+	Number string `map:"number,redact=last4"`
+	Holder string `map:"holder"`
+}
+
+type redactUser struct {
+	Name     string                `map:"name"`
+	Password string                `map:"password,redact"`
+	Token    []byte                `map:"token,sensitive=hash"`
+	PIN      int                   `map:"pin,sensitive"`
+	Empty    string                `map:"empty,redact"`
+	Card     redactCard            `map:"card"`
+	Cards    []*redactCard         `map:"cards"`
+	ByName   map[string]redactCard `map:"by_name"`
+	Any      interface{}           `map:"any"`
+	Self     *redactUser           `map:"-"`
+}
+
+func newRedactUser() *redactUser {
+	return &redactUser{
+		Name:     "gopher",
+		Password: "secret",
+		Token:    []byte("token"),
+		PIN:      1234,
+		Card:     redactCard{Number: "4111111111111111", Holder: "gopher"},
+		Cards:    []*redactCard{{Number: "5500000000000004"}, nil},
+		ByName:   map[string]redactCard{"a": {Number: "123"}},
+		Any:      redactCard{Number: "340000000000009"},
+	}
+}
+
+func TestParseRedactMode(t *testing.T) {
+	for s, want := range map[string]RedactMode{
+		"":      RedactDefault,
+		"full":  RedactFull,
+		"last4": RedactLast4,
+		"hash":  RedactHash,
+		"none":  RedactNone,
+	} {
+		got, err := ParseRedactMode(s)
+		require.NoError(t, err)
+		require.Equal(t, want, got)
+	}
+	_, err := ParseRedactMode("x")
+	require.Error(t, err)
+}
+
+func TestMask(t *testing.T) {
+	require.Equal(t, RedactMask, Mask("secret", RedactDefault))
+	require.Equal(t, RedactMask, Mask("secret", RedactFull))
+	require.Equal(t, RedactMask+"cret", Mask("secret", RedactLast4))
+	require.Equal(t, RedactMask, Mask("abc", RedactLast4))
+	require.Equal(t, "sha256:2bb80d537b1da3e3", Mask("secret", RedactHash))
+	require.Equal(t, "secret", Mask("secret", RedactNone))
+	require.Equal(t, "", Mask("", RedactFull))
+	require.Nil(t, Mask(nil, RedactFull))
+}
+
+func TestStruct_Redact(t *testing.T) {
+	u := newRedactUser()
+
+	m := Map(u)
+	require.Equal(t, "gopher", m["name"])
+	require.Equal(t, RedactMask, m["password"])
+	require.Equal(t, "sha256:3c469e9d6c5875d3", m["token"])
+	require.Equal(t, RedactMask, m["pin"])
+	require.Equal(t, "", m["empty"])
+	require.Equal(t, map[string]interface{}{"number": RedactMask + "1111", "holder": "gopher"}, m["card"])
+	require.Equal(t, []interface{}{map[string]interface{}{"number": RedactMask + "0004", "holder": ""}, (*redactCard)(nil)}, m["cards"])
+	require.Equal(t, map[string]interface{}{"a": map[string]interface{}{"number": RedactMask, "holder": ""}}, m["by_name"])
+	require.Equal(t, map[string]interface{}{"number": RedactMask + "0009", "holder": ""}, m["any"])
+
+	require.Equal(t, []interface{}{
+		"gopher", RedactMask, "sha256:3c469e9d6c5875d3", RedactMask, "",
+		RedactMask + "1111", "gopher",
+		[]*redactCard{{Number: RedactMask + "0004"}, nil},
+		map[string]redactCard{"a": {Number: RedactMask}},
+		RedactMask + "0009", "",
+	}, Values(u))
+	require.Equal(t, "5500000000000004", u.Cards[0].Number)
+
+	m = New(u).SetRedactMode(RedactHash).Map()
+	require.Equal(t, "sha256:2bb80d537b1da3e3", m["password"])
+	require.Equal(t, RedactMask+"1111", m["card"].(map[string]interface{})["number"])
+
+	m = New(u).SetRedactMode(RedactNone).Map()
+	require.Equal(t, "secret", m["password"])
+	require.Equal(t, 1234, m["pin"])
+	require.Equal(t, "4111111111111111", m["card"].(map[string]interface{})["number"])
+}
+
+func TestSetRedactMode(t *testing.T) {
+	defer SetRedactMode(RedactFull)
+
+	u := newRedactUser()
+	SetRedactMode(RedactNone)
+	require.Equal(t, "secret", Map(u)["password"])
+	require.Equal(t, RedactMask, New(u).SetRedactMode(RedactFull).Map()["password"])
+	require.Same(t, u, Redacted(u))
+
+	SetRedactMode(RedactDefault)
+	require.Equal(t, RedactMask, Map(u)["password"])
+}
+
+func TestRedacted(t *testing.T) {
+	u := newRedactUser()
+	u.Self = u
+
+	got, ok := Redacted(u).(*redactUser)
+	require.True(t, ok)
+	require.NotSame(t, u, got)
+	require.Same(t, got, got.Self)
+	require.Equal(t, "gopher", got.Name)
+	require.Equal(t, RedactMask, got.Password)
+	require.Nil(t, got.Token)
+	require.Zero(t, got.PIN)
+	require.Equal(t, redactCard{Number: RedactMask + "1111", Holder: "gopher"}, got.Card)
+	require.Equal(t, []*redactCard{{Number: RedactMask + "0004"}, nil}, got.Cards)
+	require.Equal(t, map[string]redactCard{"a": {Number: RedactMask}}, got.ByName)
+	require.Equal(t, redactCard{Number: RedactMask + "0009"}, got.Any)
+	require.NotContains(t, fmt.Sprintf("%v", *got), "secret")
+
+	// the original is untouched
+	require.Equal(t, "secret", u.Password)
+	require.Equal(t, "5500000000000004", u.Cards[0].Number)
+	require.Equal(t, "123", u.ByName["a"].Number)
+
+	v, ok := Redacted(*u).(redactUser)
+	require.True(t, ok)
+	require.Equal(t, RedactMask, v.Password)
+
+	type A struct {
+		Secret string `json:"secret,redact"`
+	}
+	require.Equal(t, A{RedactMask}, RedactedWithTag(A{"x"}, "json"))
+	require.Equal(t, A{"x"}, Redacted(A{"x"}))
+	require.Nil(t, Redacted(nil))
+}
+
+func TestStruct_RedactInterfaces(t *testing.T) {
+	type request struct {
+		Items []interface{}          `map:"items"`
+		Props map[string]interface{} `map:"props"`
+		Cards [2]interface{}         `map:"cards"`
+	}
+	req := request{
+		Items: []interface{}{redactCard{Number: "4111111111111111", Holder: "gopher"}, 1},
+		Props: map[string]interface{}{"card": &redactCard{Number: "5500000000000004"}},
+		Cards: [2]interface{}{redactCard{Number: "340000000000009"}},
+	}
+
+	m := Map(req)
+	require.Equal(t, []interface{}{redactCard{Number: RedactMask + "1111", Holder: "gopher"}, 1}, m["items"])
+	require.Equal(t, map[string]interface{}{"card": &redactCard{Number: RedactMask + "0004"}}, m["props"])
+	require.Equal(t, [2]interface{}{redactCard{Number: RedactMask + "0009"}}, m["cards"])
+	require.Equal(t, Values(req), []interface{}{m["items"], m["props"], m["cards"]})
+
+	// the values are not changed
+	require.Equal(t, "5500000000000004", req.Props["card"].(*redactCard).Number)
+
+	m = New(req).SetRedactMode(RedactNone).Map()
+	require.Equal(t, "4111111111111111", m["items"].([]interface{})[0].(redactCard).Number)
+}
+
+func TestRedacted_NotSensitive(t *testing.T) {
+	type plain struct {
+		Name string `map:"name"`
+	}
+	props := map[string]interface{}{"a": plain{"a"}, "b": []interface{}{1, "b"}}
+	props["self"] = props
+	items := []interface{}{&plain{"c"}, props}
+
+	// values without sensitive fields are not copied
+	m := Map(struct {
+		Props map[string]interface{} `map:"props"`
+		Items []interface{}          `map:"items"`
+	}{props, items})
+	require.Equal(t, reflect.ValueOf(props).Pointer(), reflect.ValueOf(m["props"]).Pointer())
+	require.Equal(t, reflect.ValueOf(items).Pointer(), reflect.ValueOf(m["items"]).Pointer())
+
+	got, ok := Redacted(items).([]interface{})
+	require.True(t, ok)
+	require.Equal(t, reflect.ValueOf(items).Pointer(), reflect.ValueOf(got).Pointer())
+
+	// unless an interface holds one
+	delete(props, "self")
+	props["card"] = redactCard{Number: "4111111111111111"}
+	got, ok = Redacted(items).([]interface{})
+	require.True(t, ok)
+	require.NotEqual(t, reflect.ValueOf(items).Pointer(), reflect.ValueOf(got).Pointer())
+	require.Equal(t, redactCard{Number: RedactMask + "1111"}, got[1].(map[string]interface{})["card"])
+	require.Equal(t, "4111111111111111", props["card"].(redactCard).Number)
+}
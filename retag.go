@@ -0,0 +1,106 @@
+package structs
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Retag returns the struct type derived from struct type t, with the same
+// fields, whose tags are the results of fn, ie: to add the tags of an encoder
+// to a third-party struct. Example:
+//
+//   // adds json tags mirroring the map names.
+//   rt, err := structs.Retag(reflect.TypeOf(User{}), structs.MirrorTag("map", "json"))
+//
+// Only the fields of t are retagged, the fields of nested structs keep their
+// tags, so the values of t and of the derived type, and of the pointers to
+// them, convert to each other, see ConvertRetagged(). It returns an error if t
+// is not struct, or a pointer to it, or if reflect.StructOf() can't derive the
+// type, ie: an embedded type with methods which is not the first field.
+func Retag(t reflect.Type, fn func(field reflect.StructField) reflect.StructTag) (reflect.Type, error) {
+	t = derefType(t)
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("structs: Retag of non struct type %s", t)
+	}
+
+	fields := make([]reflect.StructField, t.NumField())
+	for i := range fields {
+		field := t.Field(i)
+		field.Tag = fn(field)
+		fields[i] = field
+	}
+	rt, err := structOf(fields)
+	if err != nil {
+		// find the first field which can't be added
+		for i := range fields {
+			if _, e := structOf(fields[:i+1]); e != nil {
+				return nil, fmt.Errorf("structs: Retag of %s field %s: %w", t, fields[i].Name, e)
+			}
+		}
+		return nil, fmt.Errorf("structs: Retag of %s: %w", t, err)
+	}
+	return rt, nil
+}
+
+// structOf returns reflect.StructOf(fields), or the error it panics with.
+func structOf(fields []reflect.StructField) (t reflect.Type, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			t, err = nil, fmt.Errorf("%v", r)
+		}
+	}()
+	return reflect.StructOf(fields), nil
+}
+
+// MirrorTag returns the function for Retag() which sets the tag dst of the
+// fields to the name in the tag src, or the field name if there is none, with
+// the options "omitempty" and "string" of src, ie: `map:"name,omitempty"`
+// gets `json:"name,omitempty"`. The fields ignored by src are ignored by dst
+// as well, and the fields with a dst tag keep it. As Retag() does not retag
+// nested structs, their fields are encoded by their own dst tags, if any.
+func MirrorTag(src, dst string) func(field reflect.StructField) reflect.StructTag {
+	return func(field reflect.StructField) reflect.StructTag {
+		if _, ok := field.Tag.Lookup(dst); ok {
+			return field.Tag
+		}
+		tag := field.Tag.Get(src)
+		if tag == "-" {
+			return SetTag(field.Tag, dst, "-")
+		}
+		name, tagOpts := parseTag(tag)
+		if name == "" {
+			name = field.Name
+		}
+		for _, opt := range []string{"omitempty", "string"} {
+			if tagOpts.Contains(opt) {
+				name += "," + opt
+			}
+		}
+		return SetTag(field.Tag, dst, name)
+	}
+}
+
+// ConvertRetagged converts v, which is a struct or a pointer to struct, to
+// the struct type t, or to the pointer to t if v is a pointer, where t is
+// derived from the type of v by Retag(), or the other way round. A converted
+// pointer points to the same struct as v, so decoding into it sets v, ie:
+//
+//   rv, _ := structs.ConvertRetagged(&user, rt)
+//   err := json.Unmarshal(data, rv) // sets user
+//
+// The fields of nested structs have the tags of v in both types, see Retag().
+// It returns an error if the types differ in other than tags.
+func ConvertRetagged(v interface{}, t reflect.Type) (interface{}, error) {
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() {
+		return nil, errNilValue
+	}
+	t = derefType(t)
+	if rv.Kind() == reflect.Ptr {
+		t = reflect.PtrTo(t)
+	}
+	if !rv.Type().ConvertibleTo(t) || derefType(rv.Type()).Kind() != reflect.Struct {
+		return nil, fmt.Errorf("structs: cannot convert %s to %s", rv.Type(), t)
+	}
+	return rv.Convert(t).Interface(), nil
+}
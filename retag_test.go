@@ -0,0 +1,81 @@
+package structs
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type retagAddress struct {
+	City string `map:"city"`
+}
+
+type retagUser struct {
+	Name    string       `map:"name"`
+	Age     int          `map:"age,omitempty,string"`
+	Email   string       `map:"email" json:"mail"`
+	Secret  string       `map:"-"`
+	Address retagAddress `map:"address"`
+	Level   string
+	note    string
+}
+
+func TestRetag(t *testing.T) {
+	rt, err := Retag(reflect.TypeOf(&retagUser{}), MirrorTag("map", "json"))
+	require.NoError(t, err)
+	require.Equal(t, reflect.Struct, rt.Kind())
+	require.Equal(t, reflect.StructTag(`map:"name" json:"name"`), rt.Field(0).Tag)
+	require.Equal(t, reflect.StructTag(`map:"age,omitempty,string" json:"age,omitempty,string"`), rt.Field(1).Tag)
+	require.Equal(t, reflect.StructTag(`map:"email" json:"mail"`), rt.Field(2).Tag)
+	require.Equal(t, reflect.StructTag(`map:"-" json:"-"`), rt.Field(3).Tag)
+	require.Equal(t, reflect.StructTag(`json:"Level"`), rt.Field(5).Tag)
+
+	user := retagUser{Name: "fatih", Age: 31, Email: "a@b.c", Secret: "x", Address: retagAddress{City: "Istanbul"}}
+	v, err := ConvertRetagged(user, rt)
+	require.NoError(t, err)
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"name":"fatih","age":"31","mail":"a@b.c","address":{"City":"Istanbul"},"Level":""}`, string(data))
+
+	// decoding into the converted pointer sets the original struct
+	p, err := ConvertRetagged(&user, rt)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal([]byte(`{"name":"arslan","age":"20"}`), p))
+	require.Equal(t, "arslan", user.Name)
+	require.Equal(t, 20, user.Age)
+
+	// nested structs are not retagged
+	require.Equal(t, reflect.TypeOf(retagAddress{}), rt.Field(4).Type)
+
+	// and back
+	back, err := ConvertRetagged(v, reflect.TypeOf(retagUser{}))
+	require.NoError(t, err)
+	require.Equal(t, "fatih", back.(retagUser).Name)
+
+	_, err = ConvertRetagged(retagAddress{}, rt)
+	require.Error(t, err)
+	_, err = ConvertRetagged(nil, rt)
+	require.Error(t, err)
+
+	_, err = Retag(reflect.TypeOf(0), MirrorTag("map", "json"))
+	require.EqualError(t, err, "structs: Retag of non struct type int")
+}
+
+func TestRetag_Error(t *testing.T) {
+	type T struct {
+		Name string `map:"name"`
+		time.Time
+	}
+	_, err := Retag(reflect.TypeOf(T{}), MirrorTag("map", "json"))
+	require.ErrorContains(t, err, "structs: Retag of structs.T field Time: reflect: embedded type with methods")
+}
+
+func TestSetTag(t *testing.T) {
+	require.Equal(t, reflect.StructTag(`map:"name" json:"name,omitempty"`), SetTag(`map:"name" json:"n"`, "json", "name,omitempty"))
+	require.Equal(t, reflect.StructTag(`json:"name"`), SetTag("", "json", "name"))
+	require.Equal(t, reflect.StructTag(`map:"a\"b"`), SetTag("", "map", `a"b`))
+	require.Equal(t, reflect.StructTag(`map json:"name"`), SetTag("map", "json", "name"))
+}
@@ -0,0 +1,380 @@
+package structs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SchemaDraft is the JSON Schema dialect which Schema() emits.
+const SchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	bytesType   = reflect.TypeOf([]byte(nil))
+	errNilValue = errors.New("structs: nil value")
+)
+
+// Schema returns the JSON Schema (draft 2020-12) of the output of Map() for the
+// given struct. Only the type of s is used, so s can be a nil pointer or a
+// reflect.Type. For more info refer to SchemaWithTag() function.
+func Schema(s interface{}) ([]byte, error) {
+	return SchemaWithTag(s, DefaultTagName)
+}
+
+// SchemaWithTag returns the JSON Schema (draft 2020-12) of the output of
+// MapWithTag() for the given struct with tagName. The field names and the
+// options of tagName are handled the same as Map does:
+//
+//   - a tag value with the content of "-" ignores that particular field.
+//   - the options "omitempty" and "omitzero" make the field optional, otherwise
+//     it is required.
+//   - the option "string" makes the field a string.
+//   - the options "redact" and "sensitive" make the field a string, unless the
+//     redaction is disabled by SetRedactMode(RedactNone).
+//   - the option "format" makes the field a string, or an integer for the
+//     formats "unix", "unixmilli" and "nanos".
+//   - the option "flatten" moves the properties of a struct field into the parent.
+//   - the option "omitnested" describes a struct field as a plain object.
+//
+// Slices and arrays become arrays, maps become objects with
+// additionalProperties, time.Time becomes a date-time string and pointers
+// are nullable. Struct types referring to themselves are emitted in "$defs".
+//
+// The "description" tag becomes the description of the property, and the
+// "validate" tag, in the style of go-playground/validator, adds the
+// constraints it can express: required, min, max, len, gt, gte, lt, lte,
+// oneof, email, url, uri, uuid, ipv4 and ipv6. Example:
+//
+//   Name  string `map:"name" description:"user name" validate:"min=1,max=32"`
+//   Email string `map:"email,omitempty" validate:"email"`
+func SchemaWithTag(s interface{}, tagName string) ([]byte, error) {
+	t, ok := s.(reflect.Type)
+	if !ok {
+		if s == nil {
+			return nil, errNilValue
+		}
+		t = reflect.TypeOf(s)
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, errors.New("structs: not struct")
+	}
+
+	b := &schemaBuilder{
+		tagName:   tagName,
+		root:      t,
+		visiting:  make(map[reflect.Type]bool),
+		recursive: make(map[reflect.Type]bool),
+		defs:      make(map[string]interface{}),
+	}
+	schema, err := b.object(t)
+	if err != nil {
+		return nil, err
+	}
+	schema["$schema"] = SchemaDraft
+	if name := t.Name(); name != "" {
+		schema["title"] = name
+	}
+	if len(b.defs) > 0 {
+		schema["$defs"] = b.defs
+	}
+	return json.Marshal(schema)
+}
+
+type schemaBuilder struct {
+	tagName   string
+	root      reflect.Type
+	visiting  map[reflect.Type]bool
+	recursive map[reflect.Type]bool
+	defs      map[string]interface{}
+}
+
+// object returns the schema of the struct type t.
+func (b *schemaBuilder) object(t reflect.Type) (map[string]interface{}, error) {
+	properties := make(map[string]interface{})
+	required := make([]string, 0, t.NumField())
+	if err := b.properties(t, "", properties, &required); err != nil {
+		return nil, err
+	}
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema, nil
+}
+
+// properties fills properties and required with the fields of struct type t,
+// whose names are prefixed by prefix.
+func (b *schemaBuilder) properties(t reflect.Type, prefix string, properties map[string]interface{}, required *[]string) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		// we can't access the value of unexported fields
+		if field.PkgPath != "" {
+			continue
+		}
+		tag := field.Tag.Get(b.tagName)
+		if tag == "-" {
+			continue
+		}
+		name, tagOpts := parseTag(tag)
+		if name == "" {
+			name = field.Name
+		}
+		name = prefix + name
+
+		ft := field.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if tagOpts.Contains("flatten") && !tagOpts.Contains("omitnested") &&
+			ft.Kind() == reflect.Struct && ft != timeType {
+			if err := b.properties(ft, prefix+flattenPrefix(tagOpts), properties, required); err != nil {
+				return err
+			}
+			continue
+		}
+
+		var prop map[string]interface{}
+		var err error
+		format, hasFormat := tagOpts.Value("format")
+		switch {
+		case isRedacted(tagOpts):
+			prop = map[string]interface{}{"type": "string"}
+		case hasFormat:
+			prop = formatSchema(ft, format)
+		case tagOpts.Contains("string"):
+			prop = map[string]interface{}{"type": "string"}
+		case tagOpts.Contains("omitnested") && ft.Kind() == reflect.Struct:
+			prop = map[string]interface{}{"type": "object"}
+		default:
+			prop, err = b.schema(field.Type)
+			if err != nil {
+				return fmt.Errorf("structs: field %s: %w", field.Name, err)
+			}
+		}
+		if desc := field.Tag.Get("description"); desc != "" {
+			prop["description"] = desc
+		}
+
+		isRequired := !tagOpts.Contains("omitempty") && !tagOpts.Contains("omitzero")
+		if rules := field.Tag.Get("validate"); rules != "" {
+			if applyValidateRules(prop, ft, rules) {
+				isRequired = true
+			}
+		}
+		if isRequired {
+			*required = append(*required, name)
+		}
+		properties[name] = prop
+	}
+	return nil
+}
+
+// isRedacted reports whether the field with the tag options is masked by Map
+// with the global redaction mode.
+func isRedacted(tagOpts tagOptions) bool {
+	field, ok := fieldRedactMode(tagOpts)
+	return ok && resolveRedactMode(field, RedactDefault) != RedactNone
+}
+
+// schema returns the schema of type t.
+func (b *schemaBuilder) schema(t reflect.Type) (map[string]interface{}, error) {
+	if t.Kind() == reflect.Ptr {
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		schema, err := b.schema(t)
+		if err != nil {
+			return nil, err
+		}
+		if typ, ok := schema["type"].(string); ok {
+			schema["type"] = []string{typ, "null"}
+		}
+		return schema, nil
+	}
+
+	switch t.Kind() { // nolint: exhaustive
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return map[string]interface{}{"type": "integer"}, nil
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}, nil
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}, nil
+	case reflect.Interface:
+		return map[string]interface{}{}, nil
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 && t.Kind() == reflect.Slice {
+			return map[string]interface{}{"type": "string", "contentEncoding": "base64"}, nil
+		}
+		items, err := b.schema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		schema := map[string]interface{}{"type": "array", "items": items}
+		if t.Kind() == reflect.Array {
+			schema["minItems"] = t.Len()
+			schema["maxItems"] = t.Len()
+		}
+		return schema, nil
+	case reflect.Map:
+		values, err := b.schema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"type": "object", "additionalProperties": values}, nil
+	case reflect.Struct:
+		if t == timeType {
+			return map[string]interface{}{"type": "string", "format": "date-time"}, nil
+		}
+		if t == b.root {
+			return map[string]interface{}{"$ref": "#"}, nil
+		}
+		if b.visiting[t] {
+			b.recursive[t] = true
+			return map[string]interface{}{"$ref": "#/$defs/" + schemaDefName(t)}, nil
+		}
+		b.visiting[t] = true
+		schema, err := b.object(t)
+		delete(b.visiting, t)
+		if err != nil {
+			return nil, err
+		}
+		if b.recursive[t] {
+			b.defs[schemaDefName(t)] = schema
+			return map[string]interface{}{"$ref": "#/$defs/" + schemaDefName(t)}, nil
+		}
+		return schema, nil
+	default:
+		return nil, fmt.Errorf("unsupported type %s", t)
+	}
+}
+
+func schemaDefName(t reflect.Type) string {
+	if t.Name() == "" {
+		return strings.NewReplacer(" ", "", "{", "", "}", "", ";", "_").Replace(t.String())
+	}
+	return strings.ReplaceAll(t.PkgPath(), "/", ".") + "." + t.Name()
+}
+
+// applyValidateRules adds the constraints of the validate tag rules to
+// schema of type t. It reports whether the rules contain "required".
+func applyValidateRules(schema map[string]interface{}, t reflect.Type, rules string) bool {
+	required := false
+	for _, rule := range strings.Split(rules, ",") {
+		key, value := rule, ""
+		if i := strings.IndexByte(rule, '='); i >= 0 {
+			key, value = rule[:i], rule[i+1:]
+		}
+
+		switch key {
+		case "required":
+			required = true
+		case "email":
+			schema["format"] = "email"
+		case "url", "uri":
+			schema["format"] = "uri"
+		case "uuid", "ipv4", "ipv6":
+			schema["format"] = key
+		case "oneof":
+			enum := make([]interface{}, 0)
+			for _, v := range strings.Fields(value) {
+				if n, err := strconv.ParseFloat(v, 64); err == nil && isNumberKind(t.Kind()) {
+					enum = append(enum, n)
+				} else {
+					enum = append(enum, v)
+				}
+			}
+			schema["enum"] = enum
+		case "min", "max", "len", "gt", "gte", "lt", "lte":
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			applyValidateBound(schema, t, key, n)
+		}
+	}
+	return required
+}
+
+func applyValidateBound(schema map[string]interface{}, t reflect.Type, key string, n float64) {
+	var prefix, suffix string
+	switch t.Kind() { // nolint: exhaustive
+	case reflect.String:
+		suffix = "Length"
+	case reflect.Slice, reflect.Array:
+		suffix = "Items"
+	case reflect.Map:
+		suffix = "Properties"
+	default:
+		if !isNumberKind(t.Kind()) {
+			return
+		}
+		switch key {
+		case "min", "gte":
+			schema["minimum"] = n
+		case "max", "lte":
+			schema["maximum"] = n
+		case "gt":
+			schema["exclusiveMinimum"] = n
+		case "lt":
+			schema["exclusiveMaximum"] = n
+		case "len":
+			schema["minimum"] = n
+			schema["maximum"] = n
+		}
+		return
+	}
+
+	switch key {
+	case "min", "gte":
+		prefix = "min"
+	case "max", "lte":
+		prefix = "max"
+	case "gt":
+		prefix, n = "min", n+1
+	case "lt":
+		prefix, n = "max", n-1
+	case "len":
+		schema["min"+suffix] = int(n)
+		schema["max"+suffix] = int(n)
+		return
+	}
+	schema[prefix+suffix] = int(n)
+}
+
+func isNumberKind(k reflect.Kind) bool {
+	switch k { // nolint: exhaustive
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// formatSchema returns the schema of the values of type t formatted with the
+// option "format".
+func formatSchema(t reflect.Type, format string) map[string]interface{} {
+	switch {
+	case t == timeType && (format == string(TimeUnix) || format == string(TimeUnixMilli)),
+		t == durationType && format == string(DurationNanos):
+		return map[string]interface{}{"type": "integer"}
+	case t == timeType && format == string(TimeRFC3339):
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	default:
+		return map[string]interface{}{"type": "string"}
+	}
+}
@@ -0,0 +1,170 @@
+package structs
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type schemaAddress struct {
+	City string `map:"city"`
+}
+
+type schemaNode struct {
+	Value    int           `map:"value"`
+	Children []*schemaNode `map:"children,omitempty"`
+}
+
+type schemaTree struct {
+	Root schemaLeaf `map:"root"`
+}
+
+type schemaLeaf struct {
+	Next *schemaLeaf `map:"next"`
+}
+
+func schemaOf(t *testing.T, s interface{}) map[string]interface{} {
+	b, err := Schema(s)
+	require.NoError(t, err)
+
+	var m map[string]interface{}
+	require.NoError(t, json.Unmarshal(b, &m))
+	return m
+}
+
+func TestSchema(t *testing.T) {
+	type User struct {
+		Name      string            `map:"name" description:"user name" validate:"min=1,max=32"`
+		Age       int               `map:"age,omitempty" validate:"required,gte=0,lt=150"`
+		Email     string            `map:"email,omitempty" validate:"email"`
+		Role      string            `map:"role" validate:"oneof=admin user"`
+		Score     float64           `map:"score,string"`
+		Tags      []string          `map:"tags"`
+		Pair      [2]int            `map:"pair"`
+		Labels    map[string]string `map:"labels"`
+		Avatar    []byte            `map:"avatar"`
+		Address   *schemaAddress    `map:"address"`
+		Home      schemaAddress     `map:",flatten"`
+		Raw       schemaAddress     `map:"raw,omitnested"`
+		CreatedAt time.Time         `map:"created_at"`
+		Any       interface{}       `map:"any"`
+		Ignored   string            `map:"-"`
+		unexport  string
+	}
+
+	want := map[string]interface{}{
+		"$schema": SchemaDraft,
+		"title":   "User",
+		"type":    "object",
+		"properties": map[string]interface{}{
+			"name": map[string]interface{}{
+				"type": "string", "description": "user name", "minLength": 1.0, "maxLength": 32.0,
+			},
+			"age":    map[string]interface{}{"type": "integer", "minimum": 0.0, "exclusiveMaximum": 150.0},
+			"email":  map[string]interface{}{"type": "string", "format": "email"},
+			"role":   map[string]interface{}{"type": "string", "enum": []interface{}{"admin", "user"}},
+			"score":  map[string]interface{}{"type": "string"},
+			"tags":   map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+			"pair":   map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "integer"}, "minItems": 2.0, "maxItems": 2.0},
+			"labels": map[string]interface{}{"type": "object", "additionalProperties": map[string]interface{}{"type": "string"}},
+			"avatar": map[string]interface{}{"type": "string", "contentEncoding": "base64"},
+			"address": map[string]interface{}{
+				"type":       []interface{}{"object", "null"},
+				"properties": map[string]interface{}{"city": map[string]interface{}{"type": "string"}},
+				"required":   []interface{}{"city"},
+			},
+			"city":       map[string]interface{}{"type": "string"},
+			"raw":        map[string]interface{}{"type": "object"},
+			"created_at": map[string]interface{}{"type": "string", "format": "date-time"},
+			"any":        map[string]interface{}{},
+		},
+		"required": []interface{}{
+			"name", "age", "role", "score", "tags", "pair", "labels",
+			"avatar", "address", "city", "raw", "created_at", "any",
+		},
+	}
+	require.Equal(t, want, schemaOf(t, (*User)(nil)))
+	require.Equal(t, want, schemaOf(t, reflect.TypeOf(User{})))
+}
+
+func TestSchema_Recursive(t *testing.T) {
+	got := schemaOf(t, schemaNode{})
+	require.Equal(t, map[string]interface{}{
+		"type": "array",
+		"items": map[string]interface{}{
+			"$ref": "#",
+		},
+	}, got["properties"].(map[string]interface{})["children"])
+
+	got = schemaOf(t, schemaTree{})
+	def := "github.com.things-go.structs.schemaLeaf"
+	require.Equal(t, map[string]interface{}{"$ref": "#/$defs/" + def}, got["properties"].(map[string]interface{})["root"])
+	require.Equal(t, map[string]interface{}{
+		def: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{"next": map[string]interface{}{"$ref": "#/$defs/" + def}},
+			"required":   []interface{}{"next"},
+		},
+	}, got["$defs"])
+}
+
+func TestSchema_Error(t *testing.T) {
+	_, err := Schema(nil)
+	require.Error(t, err)
+
+	_, err = Schema([]string{})
+	require.Error(t, err)
+
+	_, err = Schema(struct{ C chan int }{})
+	require.Error(t, err)
+}
+
+func TestSchema_Redact(t *testing.T) {
+	defer SetRedactMode(RedactFull)
+
+	type A struct {
+		PIN  int    `map:"pin,redact"`
+		Card string `map:"card,sensitive=last4"`
+	}
+
+	b, err := Schema(A{})
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"title": "A",
+		"type": "object",
+		"properties": {"pin": {"type": "string"}, "card": {"type": "string"}},
+		"required": ["pin", "card"]
+	}`, string(b))
+
+	SetRedactMode(RedactNone)
+	b, err = Schema(A{})
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"title": "A",
+		"type": "object",
+		"properties": {"pin": {"type": "integer"}, "card": {"type": "string"}},
+		"required": ["pin", "card"]
+	}`, string(b))
+}
+
+func TestSchemaWithTag(t *testing.T) {
+	type A struct {
+		Name string `json:"name"`
+		Age  int    `json:"age,omitempty"`
+	}
+
+	b, err := SchemaWithTag(A{}, "json")
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"title": "A",
+		"type": "object",
+		"properties": {"name": {"type": "string"}, "age": {"type": "integer"}},
+		"required": ["name"]
+	}`, string(b))
+}
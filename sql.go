@@ -0,0 +1,179 @@
+package structs
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Dialect is the bind variable style of a SQL database.
+type Dialect int
+
+// Dialect list
+const (
+	// DialectQuestion uses question marks: ?, ?, ?. (MySQL, SQLite)
+	DialectQuestion Dialect = iota
+	// DialectDollar uses numbered dollar signs: $1, $2, $3. (PostgreSQL)
+	DialectDollar
+	// DialectColon uses numbered colons: :1, :2, :3. (Oracle)
+	DialectColon
+	// DialectAt uses numbered at signs: @p1, @p2, @p3. (SQL Server)
+	DialectAt
+)
+
+// Placeholder returns the bind variable of the i'th (starting at 1) argument.
+func (d Dialect) Placeholder(i int) string {
+	switch d {
+	case DialectDollar:
+		return "$" + strconv.Itoa(i)
+	case DialectColon:
+		return ":" + strconv.Itoa(i)
+	case DialectAt:
+		return "@p" + strconv.Itoa(i)
+	default:
+		return "?"
+	}
+}
+
+// Columns returns the column names of the struct, which are the tag names,
+// or the field names if there is no tag name. A tag value with the content
+// of "-" ignores that particular field, and a struct, or pointer to struct,
+// field with the option of "flatten" has its columns in place of itself, so
+// the columns depend only on the type of the struct. Example:
+//
+//   // Field appears as column "user_name".
+//   Name string `db:"user_name"`
+//
+// Note that only exported fields of a struct can be accessed, non exported
+// fields will be neglected.
+func (s *Struct) Columns() []string {
+	columns := make([]string, 0, s.value.NumField())
+	iteratorColumns(s.value, s.tagName, false, func(column string, val reflect.Value) {
+		columns = append(columns, column)
+	})
+	return columns
+}
+
+// Placeholders returns the comma separated bind variables for all columns
+// in dialect, ie: "?, ?, ?" or "$1, $2, $3".
+func (s *Struct) Placeholders(dialect Dialect) string {
+	n := 0
+	iteratorColumns(s.value, s.tagName, false, func(string, reflect.Value) { n++ })
+
+	placeholders := make([]string, n)
+	for i := range placeholders {
+		placeholders[i] = dialect.Placeholder(i + 1)
+	}
+	return strings.Join(placeholders, ", ")
+}
+
+// InsertValues returns the field values in the same order as Columns(),
+// suitable as the arguments of an INSERT statement. The fields of a nil
+// pointer to a flattened struct have zero values.
+func (s *Struct) InsertValues() []interface{} {
+	values := make([]interface{}, 0, s.value.NumField())
+	iteratorColumns(s.value, s.tagName, false, func(column string, val reflect.Value) {
+		values = append(values, val.Interface())
+	})
+	return values
+}
+
+// UpdateSet returns the SET clause of an UPDATE statement in dialect for the
+// non-empty fields, ie: "name = ?, age = ?", and the values in the same order.
+// The bind variables are numbered from 1. Empty values are 0, false, "", nil,
+// empty array and empty map.
+func (s *Struct) UpdateSet(dialect Dialect) (string, []interface{}) {
+	var set []string
+	var values []interface{}
+	iteratorColumns(s.value, s.tagName, false, func(column string, val reflect.Value) {
+		if isEmptyValue(val) {
+			return
+		}
+		values = append(values, val.Interface())
+		set = append(set, column+" = "+dialect.Placeholder(len(values)))
+	})
+	return strings.Join(set, ", "), values
+}
+
+// ScanTargets returns the pointers of the fields in the same order as
+// Columns(), suitable as the destination of sql.Rows.Scan. Nil pointers to
+// flattened structs are allocated, so their fields are scanned as well. It
+// panics if the struct is not addressable, which means New was not given a
+// pointer.
+func (s *Struct) ScanTargets() []interface{} {
+	if !s.value.CanAddr() {
+		panic("structs: ScanTargets require a pointer to struct")
+	}
+	targets := make([]interface{}, 0, s.value.NumField())
+	iteratorColumns(s.value, s.tagName, true, func(column string, val reflect.Value) {
+		targets = append(targets, val.Addr().Interface())
+	})
+	return targets
+}
+
+// Columns returns the column names of the struct. For more info refer to
+// Struct types Columns() method. It panics if s's kind is not struct.
+func Columns(s interface{}) []string {
+	return ColumnsWithTag(s, DefaultTagName)
+}
+
+// ColumnsWithTag is the same as Columns() but with tagName.
+func ColumnsWithTag(s interface{}, tagName string) []string {
+	return New(s).SetTagName(tagName).Columns()
+}
+
+// ScanTargets returns the pointers of the fields. For more info refer to
+// Struct types ScanTargets() method. It panics if s is not a pointer to struct.
+func ScanTargets(s interface{}) []interface{} {
+	return ScanTargetsWithTag(s, DefaultTagName)
+}
+
+// ScanTargetsWithTag is the same as ScanTargets() but with tagName.
+func ScanTargetsWithTag(s interface{}, tagName string) []interface{} {
+	return New(s).SetTagName(tagName).ScanTargets()
+}
+
+// iteratorColumns calls f for every column of the struct v in field order,
+// descending into struct, or pointer to struct, fields marked as flatten, whose
+// columns are prefixed by the value of the option "prefix". Nil pointers are
+// allocated if alloc is true, otherwise their columns have zero values.
+func iteratorColumns(v reflect.Value, tagName string, alloc bool, f func(column string, val reflect.Value)) {
+	iteratorStructField(v, tagName, func(field reflect.StructField) bool {
+		val := v.FieldByIndex(field.Index)
+
+		name, tagOpts := parseTag(field.Tag.Get(tagName))
+		if tagOpts.Contains("flatten") {
+			if fv, ok := flattenValue(val, alloc); ok {
+				prefix := flattenPrefix(tagOpts)
+				iteratorColumns(fv, tagName, alloc, func(column string, val reflect.Value) {
+					f(prefix+column, val)
+				})
+				return true
+			}
+		}
+		if name == "" {
+			name = field.Name
+		}
+		f(name, val)
+		return true
+	})
+}
+
+// flattenValue returns the struct of the flattened field v, a struct or
+// pointer to struct. A nil pointer is allocated if alloc is true, otherwise
+// the zero value of the struct is returned.
+func flattenValue(v reflect.Value, alloc bool) (reflect.Value, bool) {
+	switch {
+	case v.Kind() == reflect.Struct:
+		return v, true
+	case v.Kind() != reflect.Ptr || v.Type().Elem().Kind() != reflect.Struct:
+		return reflect.Value{}, false
+	case !v.IsNil():
+		return v.Elem(), true
+	case alloc && v.CanSet():
+		v.Set(reflect.New(v.Type().Elem()))
+		return v.Elem(), true
+	default:
+		return reflect.New(v.Type().Elem()).Elem(), true
+	}
+}
@@ -0,0 +1,135 @@
+package structs
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDriver is a database/sql driver which returns a single fixed row for
+// every query.
+type fakeDriver struct{}
+
+type fakeConn struct{}
+
+type fakeStmt struct{}
+
+type fakeRows struct {
+	done bool
+}
+
+var fakeRow = []driver.Value{int64(1), "gopher", int64(18), "shanghai"}
+
+func (fakeDriver) Open(string) (driver.Conn, error)         { return fakeConn{}, nil }
+func (fakeConn) Prepare(string) (driver.Stmt, error)        { return fakeStmt{}, nil }
+func (fakeConn) Close() error                               { return nil }
+func (fakeConn) Begin() (driver.Tx, error)                  { return nil, driver.ErrSkip }
+func (fakeStmt) Close() error                               { return nil }
+func (fakeStmt) NumInput() int                              { return -1 }
+func (fakeStmt) Exec([]driver.Value) (driver.Result, error) { return driver.ResultNoRows, nil }
+func (fakeStmt) Query([]driver.Value) (driver.Rows, error)  { return &fakeRows{}, nil }
+func (*fakeRows) Columns() []string                         { return []string{"id", "name", "age", "city"} }
+func (*fakeRows) Close() error                              { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	copy(dest, fakeRow)
+	return nil
+}
+
+func init() {
+	sql.Register("structs_fake", fakeDriver{})
+}
+
+type sqlAddress struct {
+	City string `db:"city"`
+}
+
+type sqlUser struct {
+	ID      int64      `db:"id"`
+	Name    string     `db:"name"`
+	Age     int        `db:"age"`
+	Ignored string     `db:"-"`
+	Address sqlAddress `db:",flatten"`
+	secret  string
+}
+
+func TestStruct_Columns(t *testing.T) {
+	u := &sqlUser{ID: 1, Name: "gopher"}
+	s := New(u).SetTagName("db")
+
+	require.Equal(t, []string{"id", "name", "age", "city"}, s.Columns())
+	require.Equal(t, []string{"id", "name", "age", "city"}, ColumnsWithTag(u, "db"))
+	require.Equal(t, []string{"ID", "Name", "Age", "Ignored", "Address"}, Columns(u))
+}
+
+func TestStruct_Placeholders(t *testing.T) {
+	s := New(sqlUser{}).SetTagName("db")
+
+	require.Equal(t, "?, ?, ?, ?", s.Placeholders(DialectQuestion))
+	require.Equal(t, "$1, $2, $3, $4", s.Placeholders(DialectDollar))
+	require.Equal(t, ":1, :2, :3, :4", s.Placeholders(DialectColon))
+	require.Equal(t, "@p1, @p2, @p3, @p4", s.Placeholders(DialectAt))
+}
+
+func TestStruct_InsertValues(t *testing.T) {
+	u := sqlUser{ID: 1, Name: "gopher", Address: sqlAddress{"shanghai"}}
+
+	require.Equal(t, []interface{}{int64(1), "gopher", 0, "shanghai"}, New(u).SetTagName("db").InsertValues())
+}
+
+func TestStruct_UpdateSet(t *testing.T) {
+	u := sqlUser{Name: "gopher", Age: 18}
+
+	set, values := New(u).SetTagName("db").UpdateSet(DialectDollar)
+	require.Equal(t, "name = $1, age = $2", set)
+	require.Equal(t, []interface{}{"gopher", 18}, values)
+
+	set, values = New(sqlUser{}).SetTagName("db").UpdateSet(DialectQuestion)
+	require.Equal(t, "", set)
+	require.Empty(t, values)
+}
+
+func TestStruct_ScanTargets(t *testing.T) {
+	require.Panics(t, func() { ScanTargetsWithTag(sqlUser{}, "db") })
+
+	db, err := sql.Open("structs_fake", "")
+	require.NoError(t, err)
+	defer db.Close()
+
+	var u sqlUser
+	s := New(&u).SetTagName("db")
+	row := db.QueryRow("SELECT " + strings.Join(s.Columns(), ", ") + " FROM user")
+	require.NoError(t, row.Scan(s.ScanTargets()...))
+	require.Equal(t, sqlUser{ID: 1, Name: "gopher", Age: 18, Address: sqlAddress{"shanghai"}}, u)
+
+	require.Len(t, ScanTargetsWithTag(&u, "db"), 4)
+}
+
+func TestStruct_FlattenPointer(t *testing.T) {
+	type T struct {
+		ID      int64       `db:"id"`
+		Address *sqlAddress `db:",flatten"`
+	}
+
+	// the columns depend only on the type
+	require.Equal(t, []string{"id", "city"}, ColumnsWithTag(T{}, "db"))
+	require.Equal(t, []string{"id", "city"}, ColumnsWithTag(T{Address: &sqlAddress{}}, "db"))
+	require.Equal(t, "?, ?", New(T{}).SetTagName("db").Placeholders(DialectQuestion))
+	require.Equal(t, []interface{}{int64(1), ""}, New(T{ID: 1}).SetTagName("db").InsertValues())
+	require.Equal(t, []interface{}{int64(1), "c"}, New(T{ID: 1, Address: &sqlAddress{"c"}}).SetTagName("db").InsertValues())
+
+	// nil pointers are allocated for scanning
+	var v T
+	targets := ScanTargetsWithTag(&v, "db")
+	require.Len(t, targets, 2)
+	require.NotNil(t, v.Address)
+	*targets[1].(*string) = "shanghai"
+	require.Equal(t, "shanghai", v.Address.City)
+}
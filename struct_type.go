@@ -0,0 +1,248 @@
+package structs
+
+import (
+	"reflect"
+)
+
+// StructType encapsulates a struct type to provide several high level
+// functions around the struct type, without requiring a value of it.
+type StructType struct {
+	typ     reflect.Type
+	tagName string
+}
+
+// NewType returns a new *StructType with the struct type t, pointer types are
+// dereferenced. It panics if the t's kind is not struct.
+func NewType(t reflect.Type) *StructType {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		panic("structs: type must be a struct")
+	}
+	return &StructType{
+		t,
+		DefaultTagName,
+	}
+}
+
+// TypeOf returns a new *StructType with the struct type T. It panics if the T's
+// kind is not struct or pointer to struct.
+func TypeOf[T any]() *StructType {
+	return NewType(reflect.TypeOf((*T)(nil)).Elem())
+}
+
+// SetTagName set struct's field tag name, default is DefaultTagName.
+func (s *StructType) SetTagName(tagName string) *StructType {
+	s.tagName = tagName
+	return s
+}
+
+// Type returns the underlying struct type.
+func (s *StructType) Type() reflect.Type {
+	return s.typ
+}
+
+// Name returns the struct's type name within its package. It returns an
+// empty string for unnamed types.
+func (s *StructType) Name() string {
+	return s.typ.Name()
+}
+
+// Fields returns a slice of *FieldType. A struct tag with the content of "-"
+// ignores that particular field. Example:
+//
+//   // Field is ignored by this package.
+//   Field bool `map:"-"`
+func (s *StructType) Fields() []*FieldType {
+	return getFieldTypes(s.typ, s.tagName)
+}
+
+// Names returns a slice of field names, the same as Struct types Names() method.
+func (s *StructType) Names() []string {
+	fields := s.Fields()
+	names := make([]string, 0, len(fields))
+	for _, field := range fields {
+		names = append(names, field.Name())
+	}
+	return names
+}
+
+// FieldTypes returns a slice of field types in the same order as Names().
+func (s *StructType) FieldTypes() []reflect.Type {
+	fields := s.Fields()
+	types := make([]reflect.Type, 0, len(fields))
+	for _, field := range fields {
+		types = append(types, field.Type())
+	}
+	return types
+}
+
+// TagNames returns a slice of the names of the fields in the tag, or the field
+// names if there is no name in the tag, in the same order as Names(). Like
+// Names(), it includes the unexported fields, which Map() skips, and the
+// flattened fields by their own names.
+func (s *StructType) TagNames() []string {
+	fields := s.Fields()
+	names := make([]string, 0, len(fields))
+	for _, field := range fields {
+		names = append(names, field.TagName())
+	}
+	return names
+}
+
+// FieldByTag returns the field whose tag name, or field name if there is no
+// name in the tag, is name, which may be an unexported field, see TagNames().
+// The boolean returns true if the field was found.
+func (s *StructType) FieldByTag(name string) (*FieldType, bool) {
+	for _, field := range s.Fields() {
+		if field.TagName() == name {
+			return field, true
+		}
+	}
+	return nil, false
+}
+
+// Field returns the field by its field name. The boolean returns true if the
+// field was found.
+func (s *StructType) Field(name string) (*FieldType, bool) {
+	field, ok := s.typ.FieldByName(name)
+	if !ok {
+		return nil, false
+	}
+	return &FieldType{
+		field:      field,
+		defaultTag: s.tagName,
+	}, true
+}
+
+// Walk walks the exported fields of the struct type recursively in depth-first
+// order, calling fn for each field with its dotted path of tag names, ie:
+// "address.city". Fields of struct or pointer to struct type are descended
+// into unless the field has the option of "omitnested" or fn returns false.
+// A struct type already being walked on the current path is not descended
+// into again, so recursive types terminate.
+func (s *StructType) Walk(fn func(path string, field *FieldType) bool) {
+	walkFieldTypes(s.typ, s.tagName, "", map[reflect.Type]bool{s.typ: true}, fn)
+}
+
+// Schema returns the JSON Schema of the struct type. For more info refer to
+// SchemaWithTag() function.
+func (s *StructType) Schema() ([]byte, error) {
+	return SchemaWithTag(s.typ, s.tagName)
+}
+
+// FieldType represents a single struct field type that encapsulates high level
+// functions around the field, without requiring a value of it.
+type FieldType struct {
+	field      reflect.StructField
+	defaultTag string
+}
+
+// Name returns the name of the given field.
+func (f *FieldType) Name() string {
+	return f.field.Name
+}
+
+// TagName returns the name of the field in the tag, or the field name if there
+// is no name in the tag.
+func (f *FieldType) TagName() string {
+	name, _ := parseTag(f.field.Tag.Get(f.defaultTag))
+	if name == "" {
+		return f.field.Name
+	}
+	return name
+}
+
+// Tag returns the value associated with key in the tag string. If there is no
+// such key in the tag, Tag returns the empty string.
+func (f *FieldType) Tag(key string) string {
+	return f.field.Tag.Get(key)
+}
+
+// Type returns the type of the field.
+func (f *FieldType) Type() reflect.Type {
+	return f.field.Type
+}
+
+// Kind returns the fields kind, such as "string", "map", "bool", etc ..
+func (f *FieldType) Kind() reflect.Kind {
+	return f.field.Type.Kind()
+}
+
+// StructField returns the underlying reflect.StructField.
+func (f *FieldType) StructField() reflect.StructField {
+	return f.field
+}
+
+// IsAnonymous returns true if the given field is an anonymous field (embedded)
+func (f *FieldType) IsAnonymous() bool {
+	return f.field.Anonymous
+}
+
+// IsExported returns true if the given field is exported.
+func (f *FieldType) IsExported() bool {
+	return f.field.PkgPath == ""
+}
+
+// IsStruct returns true if the field's type is a struct or a pointer to struct.
+func (f *FieldType) IsStruct() bool {
+	return derefType(f.field.Type).Kind() == reflect.Struct
+}
+
+// Fields returns a slice of *FieldType of a nested struct or pointer to struct
+// field. It panics if field's kind is not struct or pointer to struct.
+func (f *FieldType) Fields() []*FieldType {
+	t := derefType(f.field.Type)
+	if t.Kind() != reflect.Struct {
+		panic("structs: field type must be a struct")
+	}
+	return getFieldTypes(t, f.defaultTag)
+}
+
+func derefType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+func getFieldTypes(t reflect.Type, tagName string) []*FieldType {
+	var fields []*FieldType
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if tag := field.Tag.Get(tagName); tag == "-" {
+			continue
+		}
+		fields = append(fields, &FieldType{
+			field,
+			tagName,
+		})
+	}
+	return fields
+}
+
+func walkFieldTypes(t reflect.Type, tagName, prefix string, visiting map[reflect.Type]bool, fn func(path string, field *FieldType) bool) {
+	for _, field := range getFieldTypes(t, tagName) {
+		if !field.IsExported() {
+			continue
+		}
+		path := field.TagName()
+		if prefix != "" {
+			path = prefix + "." + path
+		}
+		if !fn(path, field) {
+			continue
+		}
+
+		_, tagOpts := parseTag(field.Tag(tagName))
+		ft := derefType(field.Type())
+		if ft.Kind() != reflect.Struct || tagOpts.Contains("omitnested") || visiting[ft] {
+			continue
+		}
+		visiting[ft] = true
+		walkFieldTypes(ft, tagName, path, visiting, fn)
+		delete(visiting, ft)
+	}
+}
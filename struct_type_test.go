@@ -0,0 +1,106 @@
+package structs
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type typeAddress struct {
+	City string `map:"city"`
+	Zip  string `map:"zip"`
+}
+
+type typeUser struct {
+	Name     string       `map:"name"`
+	Age      int          `map:"age,omitempty"`
+	Ignored  bool         `map:"-"`
+	Address  *typeAddress `map:"address"`
+	Raw      typeAddress  `map:"raw,omitnested"`
+	Friend   *typeUser    `map:"friend"`
+	internal string
+}
+
+func TestNewType(t *testing.T) {
+	require.Panics(t, func() { NewType(reflect.TypeOf(1)) })
+	require.Panics(t, func() { NewType(nil) })
+	require.Panics(t, func() { TypeOf[[]string]() })
+
+	require.Equal(t, reflect.TypeOf(typeUser{}), NewType(reflect.TypeOf(&typeUser{})).Type())
+	require.Equal(t, reflect.TypeOf(typeUser{}), TypeOf[*typeUser]().Type())
+	require.Equal(t, "typeUser", TypeOf[typeUser]().Name())
+}
+
+func TestStructType(t *testing.T) {
+	s := TypeOf[typeUser]()
+
+	require.Equal(t, New(&typeUser{}).Names(), s.Names())
+	require.Equal(t, []string{"Name", "Age", "Address", "Raw", "Friend", "internal"}, s.Names())
+	require.Equal(t, []string{"name", "age", "address", "raw", "friend", "internal"}, s.TagNames())
+	require.Equal(t, []reflect.Type{
+		reflect.TypeOf(""),
+		reflect.TypeOf(0),
+		reflect.TypeOf(&typeAddress{}),
+		reflect.TypeOf(typeAddress{}),
+		reflect.TypeOf(&typeUser{}),
+		reflect.TypeOf(""),
+	}, s.FieldTypes())
+
+	f, ok := s.FieldByTag("address")
+	require.True(t, ok)
+	require.Equal(t, "Address", f.Name())
+	require.Equal(t, reflect.Ptr, f.Kind())
+	require.True(t, f.IsStruct())
+	require.True(t, f.IsExported())
+	require.False(t, f.IsAnonymous())
+	require.Equal(t, "address", f.Tag("map"))
+	require.Equal(t, "Address", f.StructField().Name)
+	require.Equal(t, []string{"city", "zip"}, []string{f.Fields()[0].TagName(), f.Fields()[1].TagName()})
+
+	_, ok = s.FieldByTag("Ignored")
+	require.False(t, ok)
+	_, ok = s.FieldByTag("Name")
+	require.False(t, ok)
+
+	f, ok = s.Field("internal")
+	require.True(t, ok)
+	require.False(t, f.IsExported())
+	require.False(t, f.IsStruct())
+	require.Panics(t, func() { f.Fields() })
+	_, ok = s.Field("missing")
+	require.False(t, ok)
+
+	s = TypeOf[typeUser]().SetTagName("json")
+	require.Equal(t, []string{"Name", "Age", "Ignored", "Address", "Raw", "Friend", "internal"}, s.TagNames())
+}
+
+func TestStructType_Walk(t *testing.T) {
+	var paths []string
+	TypeOf[typeUser]().Walk(func(path string, field *FieldType) bool {
+		paths = append(paths, path)
+		return true
+	})
+	require.Equal(t, []string{
+		"name", "age",
+		"address", "address.city", "address.zip",
+		"raw",
+		"friend",
+	}, paths)
+
+	paths = paths[:0]
+	TypeOf[typeUser]().Walk(func(path string, field *FieldType) bool {
+		paths = append(paths, path)
+		return field.Name() != "Address"
+	})
+	require.Equal(t, []string{"name", "age", "address", "raw", "friend"}, paths)
+}
+
+func TestStructType_Schema(t *testing.T) {
+	want, err := Schema((*typeAddress)(nil))
+	require.NoError(t, err)
+
+	got, err := TypeOf[typeAddress]().Schema()
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+}
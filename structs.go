@@ -19,28 +19,84 @@ type Struct struct {
 	raw     interface{}
 	value   reflect.Value
 	tagName string
+	groups  []string
+	redact  RedactMode
+
+	timeFormat     TimeFormat
+	durationFormat DurationFormat
+	keyEncoder     KeyEncoder
+	normalize      bool
+	nilPolicy      NilPolicy
+	useIsZero      bool
+	// keyErr records the first error of the map keys, see MapE().
+	keyErr *error
 }
 
 // New returns a new *Struct with the struct. It panics if the s's kind is
-// not struct.
+// not struct, or if s is a nil pointer to struct, unless the global nil policy
+// is NilAsZero, see SetNilPolicy.
 func New(s interface{}) *Struct {
 	value, err := structVal(s)
-	if err != nil {
+	switch {
+	case errors.Is(err, errNilStruct) && resolveNilPolicy(NilDefault) == NilAsZero:
+		value = reflect.New(value.Type().Elem()).Elem()
+	case errors.Is(err, errNilStruct):
+		panic(fmt.Sprintf("structs: nil pointer to struct %T", s))
+	case err != nil:
 		panic("structs: field must be a struct, " + err.Error())
 	}
 	return &Struct{
-		s,
-		value,
-		DefaultTagName,
+		raw:     s,
+		value:   value,
+		tagName: DefaultTagName,
 	}
 }
 
+// inherit returns a new *Struct with the nested struct v, which has the same
+// settings as s.
+func (s *Struct) inherit(v interface{}) *Struct {
+	ss := New(v)
+	ss.tagName = s.tagName
+	ss.groups = s.groups
+	ss.redact = s.redact
+	ss.timeFormat = s.timeFormat
+	ss.durationFormat = s.durationFormat
+	ss.keyEncoder = s.keyEncoder
+	ss.normalize = s.normalize
+	ss.nilPolicy = s.nilPolicy
+	ss.useIsZero = s.useIsZero
+	ss.keyErr = s.keyErr
+	return ss
+}
+
+// clone returns a copy of s, whose settings are changed without changing s.
+func (s *Struct) clone() *Struct {
+	ss := *s
+	return &ss
+}
+
 // SetTagName set struct's field tag name, default is  DefaultTagName.
 func (s *Struct) SetTagName(tagName string) *Struct {
 	s.tagName = tagName
 	return s
 }
 
+// SetTimeFormat sets the format of the time.Time values, including the
+// values of nested structs, slices and maps, in the output of Map() and
+// Values(), default is TimeDefault. The option of "format" of a field
+// overrides it.
+func (s *Struct) SetTimeFormat(format TimeFormat) *Struct {
+	s.timeFormat = format
+	return s
+}
+
+// SetDurationFormat sets the format of the time.Duration values, the same as
+// SetTimeFormat(), default is DurationDefault.
+func (s *Struct) SetDurationFormat(format DurationFormat) *Struct {
+	s.durationFormat = format
+	return s
+}
+
 // Map converts the given struct to a map[string]interface{}, where the keys
 // of the map are the field names and the values of the map the associated
 // values of the fields. The default key string is the struct field name but
@@ -62,11 +118,40 @@ func (s *Struct) SetTagName(tagName string) *Struct {
 //   Field *Animal `map:"field,string"`
 //
 // A tag value with the option of "flatten" used in a struct field is to flatten its fields
-// in the output map. Example:
+// in the output map, or the fields of the struct it points to unless nil. Example:
 //
 //   // The FieldStruct's fields will be flattened into the output map.
 //   FieldStruct time.Time `structs:",flatten"`
 //
+// The keys of the flattened fields are prefixed by the value of the option of
+// "prefix". Example:
+//
+//   // The Address's fields appear in map as "addr_city", "addr_zip".
+//   Address Address `map:",flatten,prefix=addr_"`
+//
+// A tag value with the option of "format" formats the value, time.Time by its
+// Format method with the value of the option as layout, others by fmt.Sprintf.
+// Commas in the value are kept in single quotes. Example:
+//
+//   // Field appears in map as "2006-01-02".
+//   Field time.Time `map:"myName,format=2006-01-02"`
+//
+//   // Field appears in map as "3.14".
+//   Field float64 `map:"myName,format=%.2f"`
+//
+//   // Field appears in map as "Jan 2, 2006".
+//   Field time.Time `map:"myName,format='Jan 2, 2006'"`
+//
+// The time.Time formats "rfc3339", "unix" and "unixmilli", and the
+// time.Duration formats "string" and "nanos" are the same as TimeFormat and
+// DurationFormat. Example:
+//
+//   // Field appears in map as the int64 of the Unix seconds.
+//   Field time.Time `map:"myName,format=unix"`
+//
+//   // Field appears in map as "1m30s".
+//   Field time.Duration `map:"myName,format=string"`
+//
 // A tag value with the option of "omitnested" stops iterating further if the type
 // is a struct. Example:
 //
@@ -85,6 +170,17 @@ func (s *Struct) SetTagName(tagName string) *Struct {
 //   // the field is skipped if empty.
 //   Field string `map:",omitempty"`
 //
+// A tag value with the option of "omitzero" ignores that particular field if
+// the field value is zero, by its IsZero() bool method if any, see
+// IsZeroValue(), which omits structs as well. Example:
+//
+//   // Field is skipped if its IsZero method returns true.
+//   Field time.Time `map:"myName,omitzero"`
+//
+// The keys of nested maps of structs are converted by MapKey(), and by the
+// encoder of SetKeyEncoder() for other keys, ie: structs. A key which is not
+// supported is formatted by fmt.Sprint(), see MapE() to get its error.
+//
 // Note that only exported fields of a struct can be accessed, non exported
 // fields will be neglected.
 func (s *Struct) Map() map[string]interface{} {
@@ -108,13 +204,35 @@ func (s *Struct) FillMap(out map[string]interface{}) {
 		if tagName != "" {
 			name = tagName
 		}
+		if !s.inGroups(tagOpts) {
+			return true
+		}
 
 		// if the value is a zero value and the field is marked as omitempty do
 		// not include
 		if tagOpts.Contains("omitempty") && isEmptyValue(val) {
 			return true
 		}
+		if tagOpts.Contains("omitzero") && isOmitZero(val) {
+			return true
+		}
+		val, ok := s.nilValue(val)
+		if !ok {
+			return true
+		}
+		if mode, ok := s.redactMode(tagOpts); ok {
+			out[name] = maskValue(val, mode)
+			return true
+		}
+		if format, ok := tagOpts.Value("format"); ok {
+			out[name] = formatValue(val, format)
+			return true
+		}
 		if tagOpts.Contains("string") {
+			if tv, ok := s.timeValue(val); ok {
+				out[name] = fmt.Sprint(tv)
+				return true
+			}
 			if str := toString(val); str != nil {
 				out[name] = str
 				return true
@@ -126,15 +244,16 @@ func (s *Struct) FillMap(out map[string]interface{}) {
 
 		if !tagOpts.Contains("omitnested") {
 			finalVal = s.nested(val)
-			if val.Kind() == reflect.Map || val.Kind() == reflect.Struct {
-				isSubStruct = true
+			if val.Kind() == reflect.Map || val.Kind() == reflect.Struct || val.Kind() == reflect.Ptr {
+				_, isSubStruct = finalVal.(map[string]interface{})
 			}
 		} else {
 			finalVal = val.Interface()
 		}
 		if isSubStruct && tagOpts.Contains("flatten") {
+			prefix := flattenPrefix(tagOpts)
 			for k := range finalVal.(map[string]interface{}) {
-				out[k] = finalVal.(map[string]interface{})[k]
+				out[prefix+k] = finalVal.(map[string]interface{})[k]
 			}
 		} else {
 			out[name] = finalVal
@@ -163,6 +282,15 @@ func (s *Struct) FillMap(out map[string]interface{}) {
 //   // Field is skipped if empty
 //   Field string `map:",omitempty"`
 //
+// A tag value with the option of "omitzero" ignores that particular field if
+// the field value is zero, the same as Map. Example:
+//
+//   // Field is skipped if zero
+//   Field time.Time `map:",omitzero"`
+//
+// The fields of nested structs are read with the tag name of s, the same as
+// Map.
+//
 // Note that only exported fields of a struct can be accessed, non exported
 // fields  will be neglected.
 func (s *Struct) Values() []interface{} {
@@ -171,25 +299,56 @@ func (s *Struct) Values() []interface{} {
 		val := s.value.FieldByName(field.Name)
 
 		_, tagOpts := parseTag(field.Tag.Get(s.tagName))
+		if !s.inGroups(tagOpts) {
+			return true
+		}
 
 		// if the value is a zero value and the field is marked as omitempty do
 		// not include
 		if tagOpts.Contains("omitempty") && isEmptyValue(val) {
 			return true
 		}
+		if tagOpts.Contains("omitzero") && isOmitZero(val) {
+			return true
+		}
+		val, ok := s.nilValue(val)
+		if !ok {
+			return true
+		}
+		if mode, ok := s.redactMode(tagOpts); ok {
+			t = append(t, maskValue(val, mode))
+			return true
+		}
+		if format, ok := tagOpts.Value("format"); ok {
+			t = append(t, formatValue(val, format))
+			return true
+		}
 		if tagOpts.Contains("string") {
+			if tv, ok := s.timeValue(val); ok {
+				t = append(t, fmt.Sprint(tv))
+				return true
+			}
 			if str := toString(val); str != nil {
 				t = append(t, str)
 				return true
 			}
 		}
 
-		if IsStruct(val.Interface()) && !tagOpts.Contains("omitnested") {
+		if tv, ok := s.timeValue(val); ok && !tagOpts.Contains("omitnested") {
+			t = append(t, tv)
+			return true
+		}
+
+		switch {
+		case tagOpts.Contains("omitnested"):
+			t = append(t, val.Interface())
+		case IsStruct(val.Interface()):
 			// look out for embedded structs, and convert them to a
 			// []interface{} to be added to the final values slice
-			t = append(t, Values(val.Interface())...)
-		} else {
-			t = append(t, val.Interface())
+			t = append(t, s.inherit(val.Interface()).Values()...)
+		default:
+			// slices, maps and interfaces may hold sensitive fields
+			t = append(t, s.redacted(val))
 		}
 		return true
 	})
@@ -219,6 +378,12 @@ func (s *Struct) Names() []string {
 
 	names := make([]string, 0, len(fields))
 	for _, field := range fields {
+		if _, tagOpts := parseTag(field.Tag(s.tagName)); !s.inGroups(tagOpts) {
+			continue
+		}
+		if _, ok := s.nilValue(field.value); !ok {
+			continue
+		}
 		names = append(names, field.Name())
 	}
 	return names
@@ -264,23 +429,28 @@ func (s *Struct) Field(name string) (*Field, bool) {
 //   Field time.Time     `map:"myName,omitnested"`
 //   Field *http.Request `map:",omitnested"`
 //
+// The fields of nested structs are checked with the tag name of s.
+//
 // Note that only exported fields of a struct can be accessed, non exported
 // fields  will be neglected. It panics if s's kind is not struct.
 func (s *Struct) IsZero() (b bool) {
 	b = true
 	iteratorStructField(s.value, s.tagName, func(field reflect.StructField) bool {
-		val := s.value.FieldByName(field.Name)
+		val, ok := s.nilValue(s.value.FieldByName(field.Name))
+		if !ok {
+			return true
+		}
 
 		_, tagOpts := parseTag(field.Tag.Get(s.tagName))
-		if IsStruct(val.Interface()) && !tagOpts.Contains("omitnested") {
-			ok := IsZero(val.Interface())
+		if IsStruct(val.Interface()) && !tagOpts.Contains("omitnested") && !s.hasZeroMethod(val) {
+			ok := s.inherit(val.Interface()).IsZero()
 			if !ok {
 				b = false
 				return false
 			}
 			return true
 		}
-		if !isEmptyWithAll(val) {
+		if !s.isZeroValue(val) {
 			b = false
 			return false
 		}
@@ -304,15 +474,20 @@ func (s *Struct) IsZero() (b bool) {
 //   Field time.Time     `map:"myName,omitnested"`
 //   Field *http.Request `map:",omitnested"`
 //
+// The fields of nested structs are checked with the tag name of s.
+//
 // Note that only exported fields of a struct can be accessed, non exported
 // fields  will be neglected. It panics if s's kind is not struct.
 func (s *Struct) HasZero() (b bool) {
 	iteratorStructField(s.value, s.tagName, func(field reflect.StructField) bool {
-		val := s.value.FieldByName(field.Name)
+		val, ok := s.nilValue(s.value.FieldByName(field.Name))
+		if !ok {
+			return true
+		}
 
 		_, tagOpts := parseTag(field.Tag.Get(s.tagName))
-		if IsStruct(val.Interface()) && !tagOpts.Contains("omitnested") {
-			ok := HasZero(val.Interface())
+		if IsStruct(val.Interface()) && !tagOpts.Contains("omitnested") && !s.hasZeroMethod(val) {
+			ok := s.inherit(val.Interface()).HasZero()
 			if ok {
 				b = true
 				return false
@@ -320,7 +495,7 @@ func (s *Struct) HasZero() (b bool) {
 			return true
 		}
 
-		if isEmptyWithAll(val) {
+		if s.isZeroValue(val) {
 			b = true
 			return false
 		}
@@ -451,18 +626,26 @@ func MapSlice(s interface{}) []map[string]interface{} {
 }
 
 // MapSliceWithTag converts the given struct slice to a []map[string]interface{} with tagName.
-// It returns empty []map[string]interface{} if s is not a slice struct.
+// It returns empty []map[string]interface{} if s is not a slice struct. Nil
+// pointers are handled by the global nil policy, see SetNilPolicy.
 func MapSliceWithTag(s interface{}, tagName string) []map[string]interface{} {
 	if s == nil {
 		return make([]map[string]interface{}, 0)
 	}
 	v := reflect.Indirect(reflect.ValueOf(s))
 	if (v.Type().Kind() == reflect.Slice && !v.IsNil() || v.Type().Kind() == reflect.Array) &&
-		v.IsValid() && v.Len() != 0 && reflect.Indirect(v.Index(0)).Kind() == reflect.Struct {
+		v.IsValid() && v.Len() != 0 && derefType(v.Type().Elem()).Kind() == reflect.Struct {
 		length := v.Len()
-		result := make([]map[string]interface{}, length)
+		result := make([]map[string]interface{}, 0, length)
 		for i := 0; i < length; i++ {
-			result[i] = MapWithTag(v.Index(i).Interface(), tagName)
+			e, ok := applyNilPolicy(v.Index(i), NilDefault)
+			switch {
+			case !ok:
+			case isNilStruct(e):
+				result = append(result, nil)
+			default:
+				result = append(result, MapWithTag(e.Interface(), tagName))
+			}
 		}
 		return result
 	}
@@ -472,6 +655,12 @@ func MapSliceWithTag(s interface{}, tagName string) []map[string]interface{} {
 // nested retrieves recursively all types for the given value and returns the
 // nested value.
 func (s *Struct) nested(val reflect.Value) interface{} {
+	if s.normalize {
+		return s.normalized(val)
+	}
+	if tv, ok := s.timeValue(val); ok {
+		return tv
+	}
 	var finalVal interface{}
 
 	v := reflect.ValueOf(val.Interface())
@@ -481,7 +670,7 @@ func (s *Struct) nested(val reflect.Value) interface{} {
 
 	switch v.Kind() {
 	case reflect.Struct:
-		m := New(val.Interface()).SetTagName(s.tagName).Map()
+		m := s.inherit(val.Interface()).Map()
 
 		// do not add the converted value if there are no exported fields, ie:
 		// time.Time
@@ -509,34 +698,40 @@ func (s *Struct) nested(val reflect.Value) interface{} {
 				mapElem.Elem().Kind() == reflect.Struct) {
 			m := make(map[string]interface{}, val.Len())
 			for _, k := range val.MapKeys() {
-				m[k.String()] = s.nested(val.MapIndex(k))
+				if e, ok := s.nilValue(val.MapIndex(k)); ok {
+					m[s.keyString(k)] = s.nested(e)
+				}
 			}
 			finalVal = m
 			break
 		}
 
-		// TODO(arslan): should this be optional?
-		finalVal = val.Interface()
+		// other maps are passed as is, unless normalized, see SetNormalize,
+		// with the sensitive fields of their values masked
+		finalVal = s.redacted(val)
 	case reflect.Slice, reflect.Array:
 		if val.Type().Kind() == reflect.Interface {
-			finalVal = val.Interface()
+			finalVal = s.redacted(val)
 			break
 		}
 
-		// TODO(arslan): should this be optional?
-		// do not iterate of non struct types, just pass the value. Ie: []int,
-		// []string, co... We only iterate further if it's a struct.
+		// do not iterate of non struct types, just pass the value, unless
+		// normalized, see SetNormalize. Ie: []int, []string, co... We only
+		// iterate further if it's a struct.
 		// i.e []foo or []*foo
 		if val.Type().Elem().Kind() != reflect.Struct &&
 			!(val.Type().Elem().Kind() == reflect.Ptr &&
 				val.Type().Elem().Elem().Kind() == reflect.Struct) {
-			finalVal = val.Interface()
+			// slices and maps of interfaces may hold sensitive fields
+			finalVal = s.redacted(val)
 			break
 		}
 
-		slices := make([]interface{}, val.Len())
+		slices := make([]interface{}, 0, val.Len())
 		for x := 0; x < val.Len(); x++ {
-			slices[x] = s.nested(val.Index(x))
+			if e, ok := s.nilValue(val.Index(x)); ok {
+				slices = append(slices, s.nested(e))
+			}
 		}
 		finalVal = slices
 	default:
@@ -550,6 +745,9 @@ func structVal(s interface{}) (reflect.Value, error) {
 	v := reflect.ValueOf(s)
 	// if pointer get the underlying element
 	for v.Kind() == reflect.Ptr {
+		if isNilStruct(v) {
+			return v, errNilStruct
+		}
 		v = v.Elem()
 	}
 	if v.Kind() != reflect.Struct {
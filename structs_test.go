@@ -1237,3 +1237,26 @@ func Test_toString(t *testing.T) {
 		})
 	}
 }
+
+func TestNested_TagName(t *testing.T) {
+	type inner struct {
+		A string `json:"a"`
+		B int    `json:"-"`
+	}
+	type outer struct {
+		Inner inner `json:"inner"`
+	}
+
+	// the nested fields are read with the tag name of the parent
+	v := outer{Inner: inner{A: "a", B: 1}}
+	require.Equal(t, []interface{}{"a"}, ValuesWithTag(v, "json"))
+	require.Equal(t, []interface{}{"a", 1}, Values(v))
+
+	zero := outer{Inner: inner{B: 1}}
+	require.True(t, IsZeroWithTag(zero, "json"))
+	require.False(t, IsZero(zero))
+
+	full := outer{Inner: inner{A: "a"}}
+	require.False(t, HasZeroWithTag(full, "json"))
+	require.True(t, HasZero(full))
+}
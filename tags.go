@@ -1,6 +1,10 @@
 package structs
 
 import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
 	"strings"
 	"unicode"
 )
@@ -10,12 +14,47 @@ import (
 type tagOptions []string
 
 // parseTag splits a struct field's tag into its name and
-// comma-separated options.
+// comma-separated options. Commas are kept in single quoted parts, ie:
+// "format='Jan 2, 2006'", and a backslash escapes the next character, ie:
+// "format=a\,b", quotes and backslashes are removed.
 func parseTag(tag string) (string, tagOptions) {
-	res := strings.Split(tag, ",")
+	if !strings.ContainsAny(tag, `'\`) {
+		res := strings.Split(tag, ",")
+		return res[0], res[1:]
+	}
+
+	var res []string
+	var b strings.Builder
+	quoted, escaped := false, false
+	for _, c := range tag {
+		switch {
+		case escaped:
+			b.WriteRune(c)
+			escaped = false
+		case c == '\\':
+			escaped = true
+		case c == '\'':
+			quoted = !quoted
+		case c == ',' && !quoted:
+			res = append(res, b.String())
+			b.Reset()
+		default:
+			b.WriteRune(c)
+		}
+	}
+	res = append(res, b.String())
 	return res[0], res[1:]
 }
 
+// ParseTag splits a struct tag into its name and options, see TagOptions.
+// Options are separated by commas, which are kept in single quoted parts, ie:
+// `map:"created_at,format='Jan 2, 2006'"`, and a backslash escapes the next
+// character.
+func ParseTag(tag string) (string, TagOptions) {
+	name, opts := parseTag(tag)
+	return name, TagOptions(opts)
+}
+
 // Contains reports whether a comma-separated list of options
 // contains a particular substr flag. substr must be surrounded by a
 // string boundary or commas.
@@ -31,6 +70,117 @@ func (o tagOptions) Contains(optionName string) bool {
 	return false
 }
 
+// Value returns the value of a key=value option, ie: "groups=admin|owner".
+// The boolean returns true if the option was found.
+func (o tagOptions) Value(optionName string) (string, bool) {
+	for _, s := range o {
+		if strings.HasPrefix(s, optionName) && len(s) > len(optionName) && s[len(optionName)] == '=' {
+			return s[len(optionName)+1:], true
+		}
+	}
+	return "", false
+}
+
+// TagOptions is the slice of the comma-separated options of a struct tag,
+// following the name, ie: "omitempty", "groups=admin|owner".
+type TagOptions []string
+
+// Has reports whether the options contain the flag option opt.
+func (o TagOptions) Has(opt string) bool {
+	return tagOptions(o).Contains(opt)
+}
+
+// Value returns the value of the key=value option opt. The boolean returns
+// true if the option was found.
+func (o TagOptions) Value(opt string) (string, bool) {
+	return tagOptions(o).Value(opt)
+}
+
+// structTagPair is a key:"value" pair of a struct tag, whose value is
+// unquoted.
+type structTagPair struct {
+	key   string
+	value string
+}
+
+// parseStructTag returns the pairs of tag, which must be in the conventional
+// format of struct tags, a space separated list of key:"value" pairs, the same
+// as reflect.StructTag.Lookup() parses them. The pairs before a malformed one
+// are returned with the error.
+func parseStructTag(tag string) ([]structTagPair, error) {
+	var pairs []structTagPair
+	for tag != "" {
+		i := 0
+		for i < len(tag) && tag[i] == ' ' {
+			i++
+		}
+		tag = tag[i:]
+		if tag == "" {
+			break
+		}
+
+		i = 0
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+			i++
+		}
+		if i == 0 || i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
+			return pairs, fmt.Errorf("malformed struct tag %q", tag)
+		}
+		key := tag[:i]
+		tag = tag[i+1:]
+
+		i = 1
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(tag) {
+			return pairs, fmt.Errorf("unterminated value of struct tag key %q", key)
+		}
+		value, err := strconv.Unquote(tag[:i+1])
+		if err != nil {
+			return pairs, fmt.Errorf("invalid value of struct tag key %q", key)
+		}
+		pairs = append(pairs, structTagPair{key: key, value: value})
+		tag = tag[i+1:]
+		if tag != "" && !strings.HasPrefix(tag, " ") {
+			return pairs, errors.New("struct tag pairs must be separated by spaces")
+		}
+	}
+	return pairs, nil
+}
+
+// SetTag returns tag with the value of key set to value, which replaces the
+// existing value of key in place, or is appended, ie:
+//
+//   SetTag(`map:"name" json:"n"`, "json", "name,omitempty") // => `map:"name" json:"name,omitempty"`
+//
+// A malformed tag is kept as is, with the pair appended.
+func SetTag(tag reflect.StructTag, key, value string) reflect.StructTag {
+	pairs, err := parseStructTag(string(tag))
+	if err != nil {
+		return reflect.StructTag(strings.TrimSpace(string(tag)+" "+key+":"+strconv.Quote(value)))
+	}
+	found := false
+	for i := range pairs {
+		if pairs[i].key == key {
+			pairs[i].value = value
+			found = true
+		}
+	}
+	if !found {
+		pairs = append(pairs, structTagPair{key: key, value: value})
+	}
+
+	parts := make([]string, 0, len(pairs))
+	for _, p := range pairs {
+		parts = append(parts, p.key+":"+strconv.Quote(p.value))
+	}
+	return reflect.StructTag(strings.Join(parts, " "))
+}
+
 func isValidTag(s string) bool {
 	if s == "" {
 		return false
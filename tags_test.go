@@ -1,6 +1,7 @@
 package structs
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -31,6 +32,24 @@ func TestTagParsing(t *testing.T) {
 	}
 }
 
+func TestTagOptions_Value(t *testing.T) {
+	_, opts := parseTag("field,groups=admin|owner,group,omitempty")
+	for _, tt := range []struct {
+		opt   string
+		want  string
+		found bool
+	}{
+		{"groups", "admin|owner", true},
+		{"group", "", false},
+		{"omitempty", "", false},
+		{"bar", "", false},
+	} {
+		if v, ok := opts.Value(tt.opt); v != tt.want || ok != tt.found {
+			t.Errorf("Value(%q) = %q, %v, want %q, %v", tt.opt, v, ok, tt.want, tt.found)
+		}
+	}
+}
+
 func Test_isValidTag(t *testing.T) {
 	tests := []struct {
 		name string
@@ -66,3 +85,22 @@ func Test_isValidTag(t *testing.T) {
 		})
 	}
 }
+
+func TestParseTag_Quoted(t *testing.T) {
+	for _, tt := range []struct {
+		tag  string
+		name string
+		opts TagOptions
+	}{
+		{"a,format='Jan 2, 2006',omitempty", "a", TagOptions{"format=Jan 2, 2006", "omitempty"}},
+		{`a,format=x\,y`, "a", TagOptions{"format=x,y"}},
+		{`a,format='it\'s'`, "a", TagOptions{"format=it's"}},
+		{`a\,b`, "a,b", TagOptions{}},
+		{"a", "a", TagOptions{}},
+	} {
+		name, opts := ParseTag(tt.tag)
+		if name != tt.name || !reflect.DeepEqual(opts, tt.opts) {
+			t.Errorf("ParseTag(%q) = %q, %q, want %q, %q", tt.tag, name, opts, tt.name, tt.opts)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package structs
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+var (
+	// SkipChildren is returned by Visitor.Enter to skip the children of the
+	// node, Leave is still called for it.
+	SkipChildren = errors.New("structs: skip children") // nolint: revive, stylecheck
+	// Stop is returned by the Visitor to stop the walk, Walk returns nil.
+	Stop = errors.New("structs: stop walk") // nolint: revive, stylecheck
+)
+
+// Node is a value in the tree of a struct visited by Walk().
+type Node struct {
+	// Path is the dotted path of the node, of the tag names of the fields, the
+	// keys of the maps, by MapKey(), and the indexes of slices and arrays, ie:
+	// "address.city", "items.0.id", the same as Accessor uses. It is empty for
+	// the root.
+	Path string
+	// Depth is the depth of the node, the root is 0.
+	Depth int
+	// Name is the last segment of Path, the tag name of a field, a map key or
+	// an index.
+	Name string
+	// Options are the options in the tag of a field.
+	Options TagOptions
+	// Field is the struct field of the node, which is nil for the root, the
+	// elements of slices and arrays, and the values of maps.
+	Field *Field
+	// Value is the value of the node, the root is the value given to Walk().
+	Value reflect.Value
+	// Parent is the parent node, which is nil for the root.
+	Parent *Node
+
+	// prefix is the prefix of the paths of the children.
+	prefix string
+}
+
+// Visitor visits the nodes of Walk().
+type Visitor interface {
+	// Enter is called for the node before its children, it returns
+	// SkipChildren to skip them, Stop to stop the walk, or an error which
+	// stops the walk and is returned by Walk().
+	Enter(node *Node) error
+	// Leave is called for the node after its children, it returns Stop to
+	// stop the walk, or an error which stops the walk and is returned by Walk().
+	Leave(node *Node) error
+}
+
+// VisitFunc is a Visitor which calls the function on Enter, and does nothing
+// on Leave.
+type VisitFunc func(node *Node) error
+
+// Enter calls f(node).
+func (f VisitFunc) Enter(node *Node) error {
+	return f(node)
+}
+
+// Leave does nothing.
+func (f VisitFunc) Leave(*Node) error {
+	return nil
+}
+
+// Walk walks the tree of the struct v with visitor, for more info refer to
+// WalkWithTag() function.
+func Walk(v interface{}, visitor Visitor) error {
+	return WalkWithTag(v, DefaultTagName, visitor)
+}
+
+// WalkWithTag walks the tree of the struct v, or a pointer to it, depth-first
+// in the order of the fields, calling visitor.Enter() and visitor.Leave() for
+// the root and for every node under it. Example:
+//
+//   err := structs.Walk(&server, structs.VisitFunc(func(n *structs.Node) error {
+//       if n.Options.Has("omitnested") {
+//           return structs.SkipChildren
+//       }
+//       fmt.Println(n.Path, n.Value)
+//       return nil
+//   }))
+//
+// The children of a struct are its exported fields, except the ones with the
+// tag of "-", and the children of a slice, array or map are its elements, the
+// map ones in the order of their keys. Non-nil pointers and interfaces are
+// walked through, and a pointer already on the path to the node is not walked
+// again. The flattened struct fields have the paths of their parents, and
+// their children the paths with the prefix of the option "prefix", the same
+// as Map() and Accessor. []byte values and the structs without exported
+// fields, ie: time.Time, have no children. It returns an error if v is not
+// struct, or a nil pointer.
+func WalkWithTag(v interface{}, tagName string, visitor Visitor) error {
+	if _, err := structVal(v); err != nil {
+		return err
+	}
+	w := &walker{tagName: tagName, visitor: visitor, visiting: make(map[visitingKey]bool)}
+	if err := w.walk(&Node{Value: reflect.ValueOf(v)}); err != nil && !errors.Is(err, Stop) {
+		return err
+	}
+	return nil
+}
+
+type visitingKey struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+type walker struct {
+	tagName  string
+	visitor  Visitor
+	visiting map[visitingKey]bool
+}
+
+// walk visits node and its children.
+func (w *walker) walk(node *Node) error {
+	err := w.visitor.Enter(node)
+	switch {
+	case errors.Is(err, SkipChildren):
+	case err != nil:
+		return err
+	default:
+		if err = w.children(node); err != nil {
+			return err
+		}
+	}
+	return w.visitor.Leave(node)
+}
+
+// children visits the children of node.
+func (w *walker) children(node *Node) error {
+	v := node.Value
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		if v.Kind() == reflect.Ptr {
+			key := visitingKey{ptr: v.Pointer(), typ: v.Type()}
+			if w.visiting[key] {
+				return nil
+			}
+			w.visiting[key] = true
+			defer delete(w.visiting, key)
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() { // nolint: exhaustive
+	case reflect.Struct:
+		return w.fields(node, v)
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return nil
+		}
+		for i := 0; i < v.Len(); i++ {
+			if err := w.walk(w.child(node, strconv.Itoa(i), v.Index(i))); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		keys := v.MapKeys()
+		names := make([]string, len(keys))
+		for i, k := range keys {
+			name, err := mapKeyString(k, nil)
+			if err != nil {
+				name = fmt.Sprint(k.Interface())
+			}
+			names[i] = name
+		}
+		index := make([]int, len(keys))
+		for i := range index {
+			index[i] = i
+		}
+		sort.SliceStable(index, func(i, j int) bool { return names[index[i]] < names[index[j]] })
+		for _, i := range index {
+			if err := w.walk(w.child(node, names[i], v.MapIndex(keys[i]))); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// fields visits the fields of the struct v, which are the children of
+// parent.
+func (w *walker) fields(parent *Node, v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fv := v.Field(i)
+		// we can't access the value of unexported fields
+		if !fv.CanInterface() || field.PkgPath != "" {
+			continue
+		}
+		tag := field.Tag.Get(w.tagName)
+		if tag == "-" {
+			continue
+		}
+		name, tagOpts := parseTag(tag)
+		if name == "" {
+			name = field.Name
+		}
+
+		node := w.child(parent, name, fv)
+		node.Options = TagOptions(tagOpts)
+		node.Field = &Field{value: fv, field: field, defaultTag: w.tagName}
+		if tagOpts.Contains("flatten") && (fv.Kind() == reflect.Struct ||
+			fv.Kind() == reflect.Ptr && !fv.IsNil() && fv.Elem().Kind() == reflect.Struct) {
+			node.Path = parent.Path
+			node.prefix = parent.prefix + flattenPrefix(tagOpts)
+		}
+		if err := w.walk(node); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// child returns the child node of parent named name with the value v.
+func (w *walker) child(parent *Node, name string, v reflect.Value) *Node {
+	path := parent.prefix + name
+	return &Node{
+		Path:   path,
+		Depth:  parent.Depth + 1,
+		Name:   name,
+		Value:  v,
+		Parent: parent,
+		prefix: path + ".",
+	}
+}
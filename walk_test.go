@@ -0,0 +1,166 @@
+package structs
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type walkItem struct {
+	ID int `map:"id"`
+}
+
+type walkLog struct {
+	Level string `map:"level"`
+}
+
+type walkNode struct {
+	Name  string                 `map:"name"`
+	Since time.Time              `map:"since"`
+	Items []walkItem             `map:"items"`
+	Meta  map[string]interface{} `map:"meta"`
+	Data  []byte                 `map:"data"`
+	Log   walkLog                `map:",flatten,prefix=log_"`
+	Next  *walkNode              `map:"next"`
+	Skip  walkItem               `map:"skip,omitnested"`
+	Any   interface{}            `map:"any"`
+	Hide  string                 `map:"-"`
+	note  string
+}
+
+type walkRecorder struct {
+	events []string
+	enter  func(node *Node) error
+}
+
+func (r *walkRecorder) Enter(node *Node) error {
+	r.events = append(r.events, fmt.Sprintf("enter %q %d", node.Path, node.Depth))
+	if r.enter != nil {
+		return r.enter(node)
+	}
+	return nil
+}
+
+func (r *walkRecorder) Leave(node *Node) error {
+	r.events = append(r.events, fmt.Sprintf("leave %q", node.Path))
+	return nil
+}
+
+func TestWalk(t *testing.T) {
+	v := &walkNode{
+		Name:  "root",
+		Items: []walkItem{{ID: 1}},
+		Meta:  map[string]interface{}{"b": 2, "a": 1},
+		Data:  []byte("data"),
+		Any:   &walkItem{ID: 3},
+	}
+	v.Next = v // cycle
+
+	var paths []string
+	err := Walk(v, VisitFunc(func(n *Node) error {
+		if n.Options.Has("omitnested") {
+			return SkipChildren
+		}
+		paths = append(paths, n.Path)
+		return nil
+	}))
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"",
+		"name",
+		"since",
+		"items",
+		"items.0",
+		"items.0.id",
+		"meta",
+		"meta.a",
+		"meta.b",
+		"data",
+		"", // flattened Log
+		"log_level",
+		"next",
+		"any",
+		"any.id",
+	}, paths)
+}
+
+func TestWalk_FlattenPointer(t *testing.T) {
+	type T struct {
+		Log  *walkLog `map:",flatten,prefix=log_"`
+		Next *walkLog `map:",flatten,prefix=next_"`
+	}
+
+	var paths []string
+	require.NoError(t, Walk(T{Log: &walkLog{}}, VisitFunc(func(n *Node) error {
+		paths = append(paths, n.Path)
+		return nil
+	})))
+	require.Equal(t, []string{"", "", "log_level", "Next"}, paths)
+}
+
+func TestWalk_Node(t *testing.T) {
+	v := walkNode{Items: []walkItem{{ID: 1}}}
+	nodes := make(map[string]*Node)
+	require.NoError(t, Walk(v, VisitFunc(func(n *Node) error {
+		nodes[n.Path] = n
+		return nil
+	})))
+
+	n := nodes["items.0.id"]
+	require.Equal(t, 3, n.Depth)
+	require.Equal(t, "id", n.Name)
+	require.Equal(t, "ID", n.Field.Name())
+	require.Equal(t, 1, n.Value.Interface())
+	require.Equal(t, "items.0", n.Parent.Path)
+	require.Nil(t, n.Parent.Field)
+	require.Equal(t, "Items", n.Parent.Parent.Field.Name())
+	require.Nil(t, n.Parent.Parent.Parent.Parent)
+
+	require.Equal(t, TagOptions{"omitnested"}, nodes["skip"].Options)
+	require.Equal(t, "Level", nodes["log_level"].Field.Name())
+	require.Equal(t, "Log", nodes["log_level"].Parent.Field.Name())
+}
+
+func TestWalk_EnterLeave(t *testing.T) {
+	v := walkNode{Items: []walkItem{{ID: 1}}}
+	r := &walkRecorder{enter: func(n *Node) error {
+		switch {
+		case n.Path == "items":
+			return SkipChildren
+		case n.Path == "data":
+			return Stop
+		}
+		return nil
+	}}
+	require.NoError(t, WalkWithTag(&v, "map", r))
+	require.Equal(t, strings.Join([]string{
+		`enter "" 0`,
+		`enter "name" 1`,
+		`leave "name"`,
+		`enter "since" 1`,
+		`leave "since"`,
+		`enter "items" 1`,
+		`leave "items"`,
+		`enter "meta" 1`,
+		`leave "meta"`,
+		`enter "data" 1`,
+	}, "\n"), strings.Join(r.events, "\n"))
+}
+
+func TestWalk_Error(t *testing.T) {
+	errBoom := errors.New("boom")
+	err := Walk(walkNode{}, VisitFunc(func(n *Node) error {
+		if n.Path == "since" {
+			return errBoom
+		}
+		return nil
+	}))
+	require.ErrorIs(t, err, errBoom)
+
+	require.Error(t, Walk(1, VisitFunc(func(*Node) error { return nil })))
+	require.ErrorIs(t, Walk((*walkNode)(nil), VisitFunc(func(*Node) error { return nil })), errNilStruct)
+}
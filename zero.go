@@ -0,0 +1,193 @@
+package structs
+
+import (
+	"reflect"
+	"sync"
+)
+
+// zeroer is implemented by the types with an IsZero method, ie: time.Time.
+type zeroer interface {
+	IsZero() bool
+}
+
+var zeroerType = reflect.TypeOf((*zeroer)(nil)).Elem()
+
+// ZeroFunc reports whether v, which is a value of the type it is registered
+// for, is zero.
+type ZeroFunc func(v interface{}) bool
+
+var zeroFuncs sync.Map // reflect.Type -> ZeroFunc
+
+// RegisterZeroFunc registers the custom emptiness predicate fn of type t,
+// which is used by the option of "omitzero", and by SetUseIsZero(), instead of
+// the IsZero method of t, ie: for types without the method. A nil fn removes
+// the predicate of t.
+func RegisterZeroFunc(t reflect.Type, fn ZeroFunc) {
+	if fn == nil {
+		zeroFuncs.Delete(t)
+		return
+	}
+	zeroFuncs.Store(t, fn)
+}
+
+// IsZeroValue reports whether v is zero as the option of "omitzero" does, by
+// the predicate registered by RegisterZeroFunc() for the type of v, or by the
+// IsZero() bool method of v, ie: of time.Time or sql.NullString, otherwise by
+// comparing it with the zero value of its type, see reflect.Value.IsZero(), so
+// an array of zero elements is zero, but an empty non-nil slice is not. A nil
+// v is zero.
+func IsZeroValue(v interface{}) bool {
+	return isOmitZero(reflect.ValueOf(v))
+}
+
+// isOmitZero reports whether v is zero, see IsZeroValue.
+func isOmitZero(v reflect.Value) bool {
+	if z, ok := zeroCheck(v); ok {
+		return z
+	}
+	return v.IsZero()
+}
+
+// SetUseIsZero sets whether the IsZero() bool methods of the values, ie: of
+// time.Time, or the predicates registered by RegisterZeroFunc(), report
+// whether they are zero, instead of comparing them with the zero value of
+// their types, default is false. The structs with the method
+// are not iterated further. It applies to IsZero, HasZero, ZeroFields and
+// NonZeroFields.
+func (s *Struct) SetUseIsZero(use bool) *Struct {
+	s.useIsZero = use
+	return s
+}
+
+// ZeroFields returns the dotted paths of tag names, ie: "address.city", of
+// the fields with zero values, recursing into nested structs, except the
+// fields with the option of "omitnested". The fields of flattened structs
+// have the paths of their parents with the prefix of the option "prefix".
+// Nil pointers to structs are handled by the nil policy, see SetNilPolicy(),
+// which are zero fields with NilAsNil. A struct tag with the content of "-"
+// ignores that particular field.
+func (s *Struct) ZeroFields() []string {
+	return s.appendZeroFields(nil, "", true)
+}
+
+// NonZeroFields returns the dotted paths of tag names of the fields with
+// non-zero values, the reverse of ZeroFields().
+func (s *Struct) NonZeroFields() []string {
+	return s.appendZeroFields(nil, "", false)
+}
+
+// ZeroFields returns the dotted paths of tag names of the zero fields of the
+// struct. For more info refer to Struct types ZeroFields() method. It panics
+// if s's kind is not struct.
+func ZeroFields(s interface{}) []string {
+	return ZeroFieldsWithTag(s, DefaultTagName)
+}
+
+// ZeroFieldsWithTag is the same as ZeroFields() but with tagName.
+func ZeroFieldsWithTag(s interface{}, tagName string) []string {
+	return New(s).SetTagName(tagName).ZeroFields()
+}
+
+// NonZeroFields returns the dotted paths of tag names of the non-zero fields
+// of the struct. For more info refer to Struct types NonZeroFields() method.
+// It panics if s's kind is not struct.
+func NonZeroFields(s interface{}) []string {
+	return NonZeroFieldsWithTag(s, DefaultTagName)
+}
+
+// NonZeroFieldsWithTag is the same as NonZeroFields() but with tagName.
+func NonZeroFieldsWithTag(s interface{}, tagName string) []string {
+	return New(s).SetTagName(tagName).NonZeroFields()
+}
+
+// appendZeroFields appends the paths of the fields of s, which are zero if
+// zero is true, or non-zero otherwise, with prefix to paths.
+func (s *Struct) appendZeroFields(paths []string, prefix string, zero bool) []string {
+	iteratorStructField(s.value, s.tagName, func(field reflect.StructField) bool {
+		name, tagOpts := parseTag(field.Tag.Get(s.tagName))
+		if name == "" {
+			name = field.Name
+		}
+		if !s.inGroups(tagOpts) {
+			return true
+		}
+		val, ok := s.nilValue(s.value.FieldByIndex(field.Index))
+		if !ok {
+			return true
+		}
+		if s.isNestedZero(val, tagOpts) {
+			sub := s.inherit(val.Interface())
+			if tagOpts.Contains("flatten") {
+				paths = sub.appendZeroFields(paths, prefix+flattenPrefix(tagOpts), zero)
+			} else {
+				paths = sub.appendZeroFields(paths, prefix+name+".", zero)
+			}
+			return true
+		}
+		if s.isZeroValue(val) == zero {
+			paths = append(paths, prefix+name)
+		}
+		return true
+	})
+	return paths
+}
+
+// isNestedZero reports whether the fields of the struct, or non-nil pointer to
+// struct, v are checked one by one.
+func (s *Struct) isNestedZero(v reflect.Value, tagOpts tagOptions) bool {
+	return !tagOpts.Contains("omitnested") && isMergeStruct(v.Type()) && !isNilStruct(v) && !s.hasZeroMethod(v)
+}
+
+// hasZeroMethod reports whether s uses the IsZero method, or the registered
+// predicate, of v.
+func (s *Struct) hasZeroMethod(v reflect.Value) bool {
+	if !s.useIsZero {
+		return false
+	}
+	_, ok := zeroCheck(v)
+	return ok
+}
+
+// isZeroValue reports whether v is zero, by its IsZero method, or the
+// registered predicate, if s uses them.
+func (s *Struct) isZeroValue(v reflect.Value) bool {
+	if s.useIsZero {
+		if z, ok := zeroCheck(v); ok {
+			return z
+		}
+	}
+	return isEmptyWithAll(v)
+}
+
+// zeroCheck returns the result of the predicate registered for the type of v,
+// or of the IsZero method of v, the boolean returns false if there is none.
+func zeroCheck(v reflect.Value) (bool, bool) {
+	if !v.IsValid() {
+		return true, true
+	}
+	if fn, ok := zeroFuncs.Load(v.Type()); ok && v.CanInterface() {
+		return fn.(ZeroFunc)(v.Interface()), true
+	}
+	return zeroMethod(v)
+}
+
+// zeroMethod returns the result of the IsZero method of v, or of the pointer
+// to v if v is addressable, the boolean returns false if there is no method.
+// Nil pointers and interfaces are zero.
+func zeroMethod(v reflect.Value) (bool, bool) {
+	if (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil() {
+		return true, v.Type().Implements(zeroerType)
+	}
+	if !v.CanInterface() {
+		return false, false
+	}
+	if z, ok := v.Interface().(zeroer); ok {
+		return z.IsZero(), true
+	}
+	if v.CanAddr() {
+		if z, ok := v.Addr().Interface().(zeroer); ok {
+			return z.IsZero(), true
+		}
+	}
+	return false, false
+}
@@ -0,0 +1,159 @@
+package structs
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type zeroAddress struct {
+	City string `map:"city"`
+	Zip  string `map:"zip"`
+}
+
+type zeroMoney struct {
+	Amount   int64  `map:"amount"`
+	Currency string `map:"currency"`
+}
+
+// IsZero reports a zero amount as zero, whatever the currency.
+func (m zeroMoney) IsZero() bool {
+	return m.Amount == 0
+}
+
+type zeroProfile struct {
+	Name     string       `map:"name"`
+	Email    string       `map:"email"`
+	Born     time.Time    `map:"born"`
+	Address  zeroAddress  `map:"address"`
+	Billing  *zeroAddress `map:"billing"`
+	Office   zeroAddress  `map:",flatten,prefix=office_"`
+	Raw      zeroAddress  `map:"raw,omitnested"`
+	Balance  zeroMoney    `map:"balance"`
+	Internal string       `map:"-"`
+}
+
+func TestStruct_ZeroFields(t *testing.T) {
+	p := zeroProfile{
+		Name:    "gopher",
+		Address: zeroAddress{City: "c"},
+		Office:  zeroAddress{Zip: "z"},
+		Balance: zeroMoney{Currency: "USD"},
+	}
+
+	require.Equal(t, []string{
+		"email", "born", "address.zip", "billing", "office_city", "raw", "balance.amount",
+	}, ZeroFields(p))
+	require.Equal(t, []string{
+		"name", "address.city", "office_zip", "balance.currency",
+	}, NonZeroFields(&p))
+
+	s := New(p).SetUseIsZero(true)
+	require.Equal(t, []string{
+		"email", "born", "address.zip", "billing", "office_city", "raw", "balance",
+	}, s.ZeroFields())
+	require.Equal(t, []string{"name", "address.city", "office_zip"}, s.NonZeroFields())
+
+	require.Equal(t, []string{
+		"email", "born", "address.zip", "billing.city", "billing.zip", "office_city", "raw", "balance.amount",
+	}, New(p).SetNilPolicy(NilAsZero).ZeroFields())
+	require.NotContains(t, New(p).SetNilPolicy(NilOmit).ZeroFields(), "billing")
+
+	type flat struct {
+		Office *zeroAddress `map:",flatten,prefix=office_"`
+		Home   *zeroAddress `map:",flatten,prefix=home_"`
+	}
+	require.Equal(t, []string{"office_zip", "Home"}, ZeroFields(flat{Office: &zeroAddress{City: "c"}}))
+
+	require.Equal(t, []string{"name"}, ZeroFieldsWithTag(struct {
+		Name string `json:"name"`
+		Age  int    `map:"age"`
+	}{Age: 1}, "json"))
+	require.Equal(t, []string{"Age"}, NonZeroFieldsWithTag(struct {
+		Name string `json:"name"`
+		Age  int    `map:"age"`
+	}{Age: 1}, "json"))
+}
+
+func TestStruct_SetUseIsZero(t *testing.T) {
+	type T struct {
+		Balance zeroMoney `map:"balance"`
+		At      time.Time `map:"at"`
+	}
+	v := T{Balance: zeroMoney{Currency: "USD"}, At: time.Time{}.In(time.FixedZone("X", 3600))}
+	require.False(t, IsZero(v))
+	require.True(t, New(v).SetUseIsZero(true).IsZero())
+	require.True(t, New(T{Balance: zeroMoney{Amount: 1}}).SetUseIsZero(true).HasZero())
+	require.False(t, New(T{Balance: zeroMoney{Amount: 1}, At: time.Now()}).SetUseIsZero(true).HasZero())
+
+	// types without IsZero are checked the same as without the option
+	type U struct {
+		Balance zeroMoney      `map:"balance"`
+		S       []string       `map:"s"`
+		M       map[string]int `map:"m"`
+	}
+	u := U{Balance: zeroMoney{Currency: "USD"}, S: []string{}, M: map[string]int{}}
+	require.Equal(t, []string{"balance.amount", "s", "m"}, ZeroFields(u))
+	require.Equal(t, []string{"balance", "s", "m"}, New(u).SetUseIsZero(true).ZeroFields())
+	require.Empty(t, New(u).SetUseIsZero(true).NonZeroFields())
+}
+
+type zeroFlag struct {
+	Set   bool
+	Value string
+}
+
+func TestStruct_OmitZero(t *testing.T) {
+	type T struct {
+		At      time.Time   `map:"at,omitzero"`
+		Empty   time.Time   `map:"empty,omitempty"`
+		Balance zeroMoney   `map:"balance,omitzero"`
+		Address zeroAddress `map:"address,omitzero"`
+		Flag    zeroFlag    `map:"flag,omitzero"`
+		Ptr     *zeroMoney  `map:"ptr,omitzero"`
+		Name    string      `map:"name,omitzero"`
+	}
+	v := T{
+		At:      time.Time{}.In(time.FixedZone("X", 3600)),
+		Balance: zeroMoney{Currency: "USD"},
+		Flag:    zeroFlag{Value: "v"},
+	}
+	m := Map(v)
+	require.Equal(t, []string{"empty", "flag"}, SortedKeys(m))
+	require.Equal(t, []interface{}{false, "v"}, Values(v))
+	require.Empty(t, Lint(v))
+
+	RegisterZeroFunc(reflect.TypeOf(zeroFlag{}), func(v interface{}) bool {
+		return !v.(zeroFlag).Set
+	})
+	defer RegisterZeroFunc(reflect.TypeOf(zeroFlag{}), nil)
+	require.Equal(t, []string{"empty"}, SortedKeys(Map(v)))
+	require.True(t, IsZeroValue(zeroFlag{Value: "v"}))
+	require.True(t, New(T{Flag: zeroFlag{Value: "v"}}).SetUseIsZero(true).IsZero())
+
+	require.True(t, IsZeroValue(nil))
+	require.True(t, IsZeroValue((*zeroMoney)(nil)))
+	require.True(t, IsZeroValue(zeroMoney{Currency: "USD"}))
+	require.False(t, IsZeroValue(zeroAddress{City: "c"}))
+	require.True(t, IsZeroValue(zeroAddress{}))
+	require.True(t, IsZeroValue([2]int{}))
+	require.False(t, IsZeroValue([2]int{0, 1}))
+	require.True(t, IsZeroValue([]int(nil)))
+	require.False(t, IsZeroValue([]int{}))
+}
+
+func TestStruct_OmitZeroArray(t *testing.T) {
+	type T struct {
+		Point [2]int         `map:"point,omitzero"`
+		Pair  [2]string      `map:"pair,omitzero"`
+		List  []int          `map:"list,omitzero"`
+		Addrs [1]zeroAddress `map:"addrs,omitzero"`
+	}
+	require.Empty(t, Map(T{}))
+	require.Equal(t, map[string]interface{}{
+		"point": [2]int{0, 1},
+		"list":  []int{},
+	}, Map(T{Point: [2]int{0, 1}, List: []int{}}))
+}